@@ -53,7 +53,7 @@ func main() {
 	)
 	mux, err := http.NewMux(
 		http.WithEnableCors(),
-		http.WithEnableRate(),
+		http.WithRateLimit(http.RateLimitConfig{Limit: http.PerSecond(100)}),
 		http.WithSimplelogger(),
 	)
 	if err != nil {
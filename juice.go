@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"go.uber.org/multierr"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -62,11 +63,16 @@ func (a *App) Run() error {
 		}
 	})
 
-	if err := g.Wait(); err != nil && !errors.Is(err, context.Canceled) {
-		return err
+	err := g.Wait()
+	if err != nil && errors.Is(err, context.Canceled) {
+		err = nil
 	}
 
-	return nil
+	for _, log := range a.opts.loggers {
+		multierr.AppendInto(&err, log.Close())
+	}
+
+	return err
 }
 
 func (a *App) Stop() error {
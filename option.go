@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 
+	"github.com/mel2oo/juice/pkg/logger"
 	"github.com/mel2oo/juice/transport"
 )
 
@@ -13,6 +14,7 @@ type options struct {
 	ctx     context.Context
 	sigs    []os.Signal
 	servers []transport.Server
+	loggers []logger.Logger
 }
 
 func Signal(sigs ...os.Signal) Option {
@@ -32,3 +34,10 @@ func Server(srv ...transport.Server) Option {
 		o.servers = srv
 	}
 }
+
+// Logger 设置在所有 Server 停止后需要 Close 的 Logger，保证异步队列与文件中的日志写完
+func Logger(logs ...logger.Logger) Option {
+	return func(o *options) {
+		o.loggers = logs
+	}
+}
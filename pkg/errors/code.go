@@ -0,0 +1,267 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// ErrDuplicateCode 重复注册的业务码
+var ErrDuplicateCode = errors.New("errors: duplicate code")
+
+// DefaultLocale 未指定或没有对应语言的描述模板时使用的语言
+var DefaultLocale = "zh"
+
+// Code 业务码定义
+type Code struct {
+	Code       int               // 业务码
+	HTTPStatus int               // HTTP 状态码，为 0 时由 GRPCCode 推导
+	GRPCCode   codes.Code        // gRPC 状态码，为 OK 时由 HTTPStatus 推导
+	Messages   map[string]string // 各语言的描述模板，按 fmt 格式化参数，如 {"zh": "用户 %s 不存在", "en": "user %s not found"}
+	Retryable  bool              // 调用方是否可以重试
+}
+
+// Message 返回 locale 的描述信息，依次尝试 locale、其主语言(如 en-US 取 en)、DefaultLocale 及任一语言
+func (c Code) Message(locale string, args ...interface{}) string {
+	tmpl, ok := c.template(locale)
+	if !ok {
+		return ""
+	}
+	if len(args) == 0 {
+		return tmpl
+	}
+	return fmt.Sprintf(tmpl, args...)
+}
+
+func (c Code) template(locale string) (string, bool) {
+	locale = strings.ToLower(locale)
+	if tmpl, ok := c.Messages[locale]; ok {
+		return tmpl, true
+	}
+	if i := strings.IndexByte(locale, '-'); i > 0 {
+		if tmpl, ok := c.Messages[locale[:i]]; ok {
+			return tmpl, true
+		}
+	}
+	if tmpl, ok := c.Messages[DefaultLocale]; ok {
+		return tmpl, true
+	}
+
+	// 没有默认语言时取排序后的第一个，保证结果稳定
+	locales := make([]string, 0, len(c.Messages))
+	for l := range c.Messages {
+		locales = append(locales, l)
+	}
+	if len(locales) == 0 {
+		return "", false
+	}
+	sort.Strings(locales)
+	return c.Messages[locales[0]], true
+}
+
+// Status 返回 HTTP 状态码
+func (c Code) Status() int {
+	if c.HTTPStatus != 0 {
+		return c.HTTPStatus
+	}
+	if c.GRPCCode != codes.OK {
+		return HTTPStatusFromGRPC(c.GRPCCode)
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCStatusCode 返回 gRPC 状态码
+func (c Code) GRPCStatusCode() codes.Code {
+	if c.GRPCCode != codes.OK {
+		return c.GRPCCode
+	}
+	return GRPCCodeFromHTTP(c.Status())
+}
+
+// DefaultRegistry 默认的业务码注册表，New、Text 等包级函数使用
+var DefaultRegistry = NewRegistry()
+
+// Register 向 DefaultRegistry 注册业务码
+func Register(codes ...Code) error {
+	return DefaultRegistry.Register(codes...)
+}
+
+// MustRegister 同 Register，重复注册时 panic
+func MustRegister(codes ...Code) {
+	if err := DefaultRegistry.Register(codes...); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup 在 DefaultRegistry 中查找业务码
+func Lookup(code int) (Code, bool) {
+	return DefaultRegistry.Lookup(code)
+}
+
+// Text 返回业务码在 DefaultLocale 下的描述模板，未注册时为空
+func Text(code int) string {
+	c, _ := DefaultRegistry.Lookup(code)
+	return c.Message(DefaultLocale)
+}
+
+// Registry 业务码注册表
+type Registry struct {
+	mu    sync.RWMutex
+	codes map[int]Code
+}
+
+func NewRegistry() *Registry {
+	return &Registry{codes: make(map[int]Code)}
+}
+
+// Register 注册业务码，已注册的业务码返回 ErrDuplicateCode，此时其余业务码不会注册
+func (r *Registry) Register(codes ...Code) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range codes {
+		if _, ok := r.codes[c.Code]; ok {
+			return fmt.Errorf("%w: %d", ErrDuplicateCode, c.Code)
+		}
+	}
+	for _, c := range codes {
+		r.codes[c.Code] = c
+	}
+	return nil
+}
+
+func (r *Registry) Lookup(code int) (Code, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.codes[code]
+	return c, ok
+}
+
+// Codes 返回已注册的业务码，按业务码排序
+func (r *Registry) Codes() []Code {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]Code, 0, len(r.codes))
+	for _, c := range r.codes {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Code < list[j].Code
+	})
+	return list
+}
+
+// Locales 返回已注册业务码中出现的语言，按字母排序
+func (r *Registry) Locales() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set := make(map[string]bool)
+	for _, c := range r.codes {
+		for l := range c.Messages {
+			set[l] = true
+		}
+	}
+
+	locales := make([]string, 0, len(set))
+	for l := range set {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// NegotiateLocale 根据 Accept-Language 选择 DefaultRegistry 中已有的语言，均未匹配时返回 DefaultLocale
+func NegotiateLocale(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return DefaultLocale
+	}
+
+	known := make(map[string]bool)
+	for _, l := range DefaultRegistry.Locales() {
+		known[l] = true
+	}
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		if known[tag] {
+			return tag
+		}
+		if i := strings.IndexByte(tag, '-'); i > 0 && known[tag[:i]] {
+			return tag[:i]
+		}
+	}
+	return DefaultLocale
+}
+
+var grpcToHTTP = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// HTTPStatusFromGRPC 按 google.rpc.Code 的约定将 gRPC 状态码映射为 HTTP 状态码
+func HTTPStatusFromGRPC(c codes.Code) int {
+	if s, ok := grpcToHTTP[c]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCodeFromHTTP 将 HTTP 状态码映射为 gRPC 状态码
+func GRPCCodeFromHTTP(status int) codes.Code {
+	switch status {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	switch {
+	case status >= 500:
+		return codes.Internal
+	case status >= 400:
+		return codes.FailedPrecondition
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,206 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain 写入 gRPC status 中 ErrorInfo 的 domain，用于识别由本包转换的错误
+var Domain = "juice"
+
+// Error 携带业务码的错误，HTTP、gRPC 状态码及描述信息取自注册的 Code
+type Error struct {
+	Code    int
+	Message string        // DefaultLocale 下的描述信息，未注册的业务码为空
+	Args    []interface{} // 描述模板的参数，本地化时使用
+	Details []proto.Message
+	cause   error
+	remote  *remote // 由 gRPC status 还原时，本地未注册业务码则使用对端的状态码与可重试性
+}
+
+type remote struct {
+	code      codes.Code
+	retryable bool
+}
+
+// New 使用 DefaultRegistry 中注册的 code 创建 Error，args 为描述模板的参数
+func New(code int, args ...interface{}) *Error {
+	c, _ := Lookup(code)
+	return &Error{
+		Code:    code,
+		Message: c.Message(DefaultLocale, args...),
+		Args:    args,
+	}
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("code=%d message=%s", e.Code, e.Message)
+	if e.cause != nil {
+		msg += ": " + e.cause.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is 业务码相同即视为同一错误，可用于 errors.Is(err, errors.New(UserNotFound))；
+// target 也可以是 transport/http 的 Error 等实现了 GetBusinessCode 的错误
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case *Error:
+		return t.Code == e.Code
+	case interface{ GetBusinessCode() int }:
+		return t.GetBusinessCode() == e.Code
+	default:
+		return false
+	}
+}
+
+// WithCause 返回以 err 为原因的副本，err 不会出现在返回给调用方的描述信息中
+func (e *Error) WithCause(err error) *Error {
+	c := *e
+	c.cause = err
+	return &c
+}
+
+// WithDetails 返回附加 details 的副本，转换为 gRPC status 时写入 details
+func (e *Error) WithDetails(details ...proto.Message) *Error {
+	c := *e
+	c.Details = append(append([]proto.Message(nil), e.Details...), details...)
+	return &c
+}
+
+// Localize 返回 locale 下的描述信息，业务码未注册或由 gRPC status 还原(模板参数未传输)时返回 Message
+func (e *Error) Localize(locale string) string {
+	c, ok := Lookup(e.Code)
+	if !ok || e.remote != nil {
+		return e.Message
+	}
+	return c.Message(locale, e.Args...)
+}
+
+// code 返回注册的 Code，未注册但由 gRPC status 还原时以对端的信息构造
+func (e *Error) code() Code {
+	if c, ok := Lookup(e.Code); ok {
+		return c
+	}
+	c := Code{Code: e.Code}
+	if e.remote != nil {
+		c.GRPCCode = e.remote.code
+		c.Retryable = e.remote.retryable
+	}
+	return c
+}
+
+// HTTPStatus 业务码未注册时为 500
+func (e *Error) HTTPStatus() int {
+	return e.code().Status()
+}
+
+// GRPCCode 业务码未注册时为 Internal
+func (e *Error) GRPCCode() codes.Code {
+	return e.code().GRPCStatusCode()
+}
+
+// Retryable 是否可以重试，以注册的 Code 为准
+func (e *Error) Retryable() bool {
+	return e.code().Retryable
+}
+
+// GRPCStatus 实现 grpc status.FromError 识别的接口，描述信息使用 DefaultLocale
+func (e *Error) GRPCStatus() *status.Status {
+	return e.Status(DefaultLocale)
+}
+
+// Status 转换为 gRPC status，details 依次为 ErrorInfo(reason 为业务码)、LocalizedMessage 及 Details
+func (e *Error) Status(locale string) *status.Status {
+	msg := e.Localize(locale)
+	st := status.New(e.GRPCCode(), msg)
+
+	details := []proto.Message{
+		&errdetails.ErrorInfo{
+			Reason: strconv.Itoa(e.Code),
+			Domain: Domain,
+			Metadata: map[string]string{
+				"retryable": strconv.FormatBool(e.Retryable()),
+			},
+		},
+		&errdetails.LocalizedMessage{Locale: locale, Message: msg},
+	}
+	details = append(details, e.Details...)
+
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails
+	}
+	return st
+}
+
+// FromError 从 err 中取出 *Error：err 链中有 *Error 时直接返回；
+// err 为 Status 生成的 gRPC status 时还原业务码、描述信息及其余 details
+func FromError(err error) (*Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, false
+	}
+	return FromStatus(st)
+}
+
+// FromStatus 从 Status 生成的 gRPC status 还原 *Error，没有本包的 ErrorInfo 时返回 false
+func FromStatus(st *status.Status) (*Error, bool) {
+	var (
+		e     *Error
+		other []proto.Message
+	)
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			if d.Domain != Domain {
+				continue
+			}
+			code, err := strconv.Atoi(d.Reason)
+			if err != nil {
+				continue
+			}
+			retryable, _ := strconv.ParseBool(d.Metadata["retryable"])
+			e = &Error{
+				Code:    code,
+				Message: st.Message(),
+				remote:  &remote{code: st.Code(), retryable: retryable},
+			}
+		case *errdetails.LocalizedMessage:
+		case proto.Message:
+			other = append(other, d)
+		}
+	}
+
+	if e == nil {
+		return nil, false
+	}
+	e.Details = other
+	return e, true
+}
+
+// CodeOf 返回 err 的业务码，不是 *Error 时返回 0
+func CodeOf(err error) int {
+	if e, ok := FromError(err); ok {
+		return e.Code
+	}
+	return 0
+}
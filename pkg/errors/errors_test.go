@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	userNotFound = 20404
+	quotaLimited = 20429
+)
+
+func init() {
+	MustRegister(
+		Code{
+			Code:       userNotFound,
+			HTTPStatus: http.StatusNotFound,
+			Messages:   map[string]string{"zh": "用户 %s 不存在", "en": "user %s not found"},
+		},
+		Code{
+			Code:      quotaLimited,
+			GRPCCode:  codes.ResourceExhausted,
+			Messages:  map[string]string{"en": "quota exceeded"},
+			Retryable: true,
+		},
+	)
+}
+
+func TestRegistry(t *testing.T) {
+	if err := Register(Code{Code: userNotFound}); !errors.Is(err, ErrDuplicateCode) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+
+	e := New(userNotFound, "juice")
+	if e.Message != "用户 juice 不存在" || e.Localize("en-US") != "user juice not found" {
+		t.Fatalf("unexpected messages %q %q", e.Message, e.Localize("en-US"))
+	}
+	if e.HTTPStatus() != http.StatusNotFound || e.GRPCCode() != codes.NotFound || e.Retryable() {
+		t.Fatalf("unexpected mapping %d %s %v", e.HTTPStatus(), e.GRPCCode(), e.Retryable())
+	}
+
+	q := New(quotaLimited)
+	if q.Message != "quota exceeded" || q.HTTPStatus() != http.StatusTooManyRequests || !q.Retryable() {
+		t.Fatalf("unexpected quota error %+v %d", q, q.HTTPStatus())
+	}
+
+	if NegotiateLocale("fr, en-GB;q=0.8") != "en" || NegotiateLocale("") != DefaultLocale {
+		t.Fatal("unexpected negotiated locale")
+	}
+}
+
+func TestErrorChain(t *testing.T) {
+	cause := errors.New("record not found")
+	err := fmt.Errorf("get user: %w", New(userNotFound, "juice").WithCause(cause))
+
+	if !errors.Is(err, New(userNotFound)) || errors.Is(err, New(quotaLimited)) || !errors.Is(err, cause) {
+		t.Fatal("unexpected errors.Is result")
+	}
+	if CodeOf(err) != userNotFound || CodeOf(cause) != 0 {
+		t.Fatalf("unexpected code %d", CodeOf(err))
+	}
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	e := New(userNotFound, "juice").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "name", Description: "unknown"}},
+	})
+
+	st := e.Status("en")
+	if st.Code() != codes.NotFound || st.Message() != "user juice not found" || len(st.Details()) != 3 {
+		t.Fatalf("unexpected status %v %v", st, st.Details())
+	}
+	if status.Code(e) != codes.NotFound {
+		t.Fatal("GRPCStatus not recognized")
+	}
+
+	got, ok := FromError(st.Err())
+	if !ok || got.Code != userNotFound || got.Message != "user juice not found" || len(got.Details) != 1 {
+		t.Fatalf("unexpected error %+v", got)
+	}
+
+	// 对端注册、本地未注册的业务码
+	remote := status.New(codes.Unavailable, "busy")
+	remote, _ = remote.WithDetails(&errdetails.ErrorInfo{Reason: "30001", Domain: Domain, Metadata: map[string]string{"retryable": "true"}})
+	got, ok = FromStatus(remote)
+	if !ok || got.GRPCCode() != codes.Unavailable || got.HTTPStatus() != http.StatusServiceUnavailable || !got.Retryable() {
+		t.Fatalf("unexpected remote error %+v", got)
+	}
+
+	if _, ok := FromError(status.Error(codes.Internal, "plain")); ok {
+		t.Fatal("plain status should not convert")
+	}
+}
@@ -1,9 +1,11 @@
 package httpclient
 
 import (
-	"bufio"
-	"bytes"
+	"fmt"
+	httpURL "net/url"
+	"time"
 
+	"github.com/mel2oo/juice/pkg/notifier"
 	"go.uber.org/zap"
 )
 
@@ -14,16 +16,56 @@ type AlarmObject interface {
 	Send(subject, body string) error
 }
 
-func onFailedAlarm(title string, raw []byte, logger *zap.Logger, alarmObject AlarmObject) {
-	buf := bytes.NewBuffer(nil)
+// AlarmFingerprintObject 可选实现，告警时会附带 method + host + http_code 组成的指纹以便去重，
+// 如 notifier.Dispatcher。
+type AlarmFingerprintObject interface {
+	AlarmObject
+	SendWithFingerprint(fingerprint, subject, body string) error
+}
+
+func alarmFingerprint(method, url string, httpCode int) string {
+	host := url
+	if u, err := httpURL.Parse(url); err == nil {
+		host = u.Host
+	}
+	return fmt.Sprintf("%s %s %d", method, host, httpCode)
+}
+
+// onFailedAlarm 使用 notifier.DefaultRegistry 中的 outbound_failure 模板渲染告警，
+// 正文格式(HTML/Markdown)由 alarmObject 决定。
+func onFailedAlarm(opt *option, method, url string, httpCode int, body []byte, err error) {
+	alert := &notifier.Alert{
+		Title:    opt.alarmTitle,
+		Method:   method,
+		URL:      url,
+		HTTPCode: httpCode,
+		Body:     string(body),
+		Time:     time.Now(),
+	}
+	if opt.trace != nil {
+		alert.TraceID = opt.trace.ID()
+	}
+	if err != nil {
+		alert.Error = fmt.Sprintf("%+v", err)
+	}
+
+	subject, content, renderErr := notifier.DefaultRegistry.Render(
+		notifier.EventOutboundFailure, "", notifier.FormatOf(opt.alarmObject), alert)
+	if renderErr != nil {
+		if opt.logger != nil {
+			opt.logger.Error("render failed alarm err", zap.Error(renderErr))
+		}
+		return
+	}
 
-	scanner := bufio.NewScanner(bytes.NewReader(raw))
-	for scanner.Scan() {
-		buf.WriteString(scanner.Text())
-		buf.WriteString(" <br/>")
+	var sendErr error
+	if o, ok := opt.alarmObject.(AlarmFingerprintObject); ok {
+		sendErr = o.SendWithFingerprint(alarmFingerprint(method, url, httpCode), subject, content)
+	} else {
+		sendErr = opt.alarmObject.Send(subject, content)
 	}
 
-	if err := alarmObject.Send(title, buf.String()); err != nil && logger != nil {
-		logger.Error("calls failed alarm err", zap.Error(err))
+	if sendErr != nil && opt.logger != nil {
+		opt.logger.Error("calls failed alarm err", zap.Error(sendErr))
 	}
 }
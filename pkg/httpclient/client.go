@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
@@ -104,33 +103,7 @@ func withoutBody(method, url string, form httpURL.Values, options ...Option) (bo
 			return
 		}
 
-		info := &struct {
-			TraceID string `json:"trace_id"`
-			Request struct {
-				Method string `json:"method"`
-				URL    string `json:"url"`
-			} `json:"request"`
-			Response struct {
-				HTTPCode int    `json:"http_code"`
-				Body     string `json:"body"`
-			} `json:"response"`
-			Error string `json:"error"`
-		}{}
-
-		if opt.trace != nil {
-			info.TraceID = opt.trace.ID()
-		}
-		info.Request.Method = method
-		info.Request.URL = url
-		info.Response.HTTPCode = httpCode
-		info.Response.Body = string(body)
-		info.Error = ""
-		if err != nil {
-			info.Error = fmt.Sprintf("%+v", err)
-		}
-
-		raw, _ := json.MarshalIndent(info, "", " ")
-		onFailedAlarm(opt.alarmTitle, raw, opt.logger, opt.alarmObject)
+		onFailedAlarm(opt, method, url, httpCode, body, err)
 
 	}()
 
@@ -322,33 +295,7 @@ func withFormBody(method, url string, form httpURL.Values, options ...Option) (b
 			return
 		}
 
-		info := &struct {
-			TraceID string `json:"trace_id"`
-			Request struct {
-				Method string `json:"method"`
-				URL    string `json:"url"`
-			} `json:"request"`
-			Response struct {
-				HTTPCode int    `json:"http_code"`
-				Body     string `json:"body"`
-			} `json:"response"`
-			Error string `json:"error"`
-		}{}
-
-		if opt.trace != nil {
-			info.TraceID = opt.trace.ID()
-		}
-		info.Request.Method = method
-		info.Request.URL = url
-		info.Response.HTTPCode = httpCode
-		info.Response.Body = string(body)
-		info.Error = ""
-		if err != nil {
-			info.Error = fmt.Sprintf("%+v", err)
-		}
-
-		raw, _ := json.MarshalIndent(info, "", " ")
-		onFailedAlarm(opt.alarmTitle, raw, opt.logger, opt.alarmObject)
+		onFailedAlarm(opt, method, url, httpCode, body, err)
 
 	}()
 
@@ -433,33 +380,7 @@ func withJSONBody(method, url string, raw json.RawMessage, options ...Option) (b
 			return
 		}
 
-		info := &struct {
-			TraceID string `json:"trace_id"`
-			Request struct {
-				Method string `json:"method"`
-				URL    string `json:"url"`
-			} `json:"request"`
-			Response struct {
-				HTTPCode int    `json:"http_code"`
-				Body     string `json:"body"`
-			} `json:"response"`
-			Error string `json:"error"`
-		}{}
-
-		if opt.trace != nil {
-			info.TraceID = opt.trace.ID()
-		}
-		info.Request.Method = method
-		info.Request.URL = url
-		info.Response.HTTPCode = httpCode
-		info.Response.Body = string(body)
-		info.Error = ""
-		if err != nil {
-			info.Error = fmt.Sprintf("%+v", err)
-		}
-
-		raw, _ := json.MarshalIndent(info, "", " ")
-		onFailedAlarm(opt.alarmTitle, raw, opt.logger, opt.alarmObject)
+		onFailedAlarm(opt, method, url, httpCode, body, err)
 
 	}()
 
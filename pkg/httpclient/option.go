@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mel2oo/juice/pkg/signature"
 	"github.com/mel2oo/juice/transport/http/middleware/trace"
 	"go.uber.org/zap"
 )
@@ -37,6 +38,7 @@ type option struct {
 	alarmObject AlarmObject
 	alarmVerify AlarmVerify
 	mock        Mock
+	signer      *signature.Signer
 }
 
 func (o *option) reset() {
@@ -52,6 +54,7 @@ func (o *option) reset() {
 	o.alarmObject = nil
 	o.alarmVerify = nil
 	o.mock = nil
+	o.signer = nil
 }
 
 func getOption() *option {
@@ -118,3 +121,10 @@ func WithOnFailedRetry(retryTimes int, retryDelay time.Duration, retryVerify Ret
 		opt.retryVerify = retryVerify
 	}
 }
+
+// WithSigner 使用 HMAC 对请求签名，每次重试都会重新签名
+func WithSigner(signer *signature.Signer) Option {
+	return func(opt *option) {
+		opt.signer = signer
+	}
+}
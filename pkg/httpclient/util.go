@@ -58,6 +58,12 @@ func doHTTP(ctx context.Context, method, url string, payload []byte, opt *option
 		req.Header.Set(key, value[0])
 	}
 
+	if opt.signer != nil {
+		if err := opt.signer.SignRequest(req, payload); err != nil {
+			return nil, -1, errors.Wrapf(err, "sign request [%s %s] err", method, url)
+		}
+	}
+
 	req.Close = true
 
 	resp, err := defaultClient.Do(req)
@@ -0,0 +1,180 @@
+package limiter
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Algorithm 并发上限的计算方式，实现需并发安全
+type Algorithm interface {
+	// Limit 当前的并发上限
+	Limit() int
+	// Update 请求结束时调用：rtt 为请求耗时，inflight 为请求开始时的并发数，
+	// dropped 表示请求因超时或下游过载失败
+	Update(rtt time.Duration, inflight int, dropped bool)
+}
+
+type fixed int
+
+// Fixed 固定的并发上限
+func Fixed(limit int) Algorithm {
+	return fixed(limit)
+}
+
+func (f fixed) Limit() int {
+	return int(f)
+}
+
+func (f fixed) Update(time.Duration, int, bool) {}
+
+// AIMDOptions NewAIMD 的配置，零值字段使用默认值
+type AIMDOptions struct {
+	InitialLimit int // 默认 20
+	MinLimit     int // 默认 1
+	MaxLimit     int // 默认 1000
+	// BackoffRatio 请求失败或超时时上限乘以该值，默认 0.9
+	BackoffRatio float64
+	// Timeout 耗时超过该值视为失败，默认 5s
+	Timeout time.Duration
+}
+
+// NewAIMD 加性增、乘性减：请求成功且并发数达到上限一半时上限加 1，失败或超时时按 BackoffRatio 降低
+func NewAIMD(opts AIMDOptions) Algorithm {
+	if opts.InitialLimit <= 0 {
+		opts.InitialLimit = 20
+	}
+	if opts.MinLimit <= 0 {
+		opts.MinLimit = 1
+	}
+	if opts.MaxLimit <= 0 {
+		opts.MaxLimit = 1000
+	}
+	if opts.BackoffRatio <= 0 || opts.BackoffRatio >= 1 {
+		opts.BackoffRatio = 0.9
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	return &aimd{opts: opts, limit: opts.InitialLimit}
+}
+
+type aimd struct {
+	mu    sync.Mutex
+	opts  AIMDOptions
+	limit int
+}
+
+func (a *aimd) Limit() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.limit
+}
+
+func (a *aimd) Update(rtt time.Duration, inflight int, dropped bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	limit := a.limit
+	if dropped || rtt > a.opts.Timeout {
+		limit = int(float64(limit) * a.opts.BackoffRatio)
+	} else if inflight*2 >= limit {
+		limit++
+	}
+	a.limit = clamp(limit, a.opts.MinLimit, a.opts.MaxLimit)
+}
+
+// GradientOptions NewGradient 的配置，零值字段使用默认值
+type GradientOptions struct {
+	InitialLimit int // 默认 20
+	MinLimit     int // 默认 1
+	MaxLimit     int // 默认 1000
+	// Smoothing 每次调整时新上限的权重，默认 0.2
+	Smoothing float64
+	// Tolerance 可容忍的耗时增长倍数，耗时超过基线的 Tolerance 倍时开始降低上限，默认 2
+	Tolerance float64
+	// Window 耗时基线(指数移动平均)的样本窗口，默认 600
+	Window int
+}
+
+// NewGradient 根据耗时梯度调整上限：以长期平均耗时为基线，当前耗时高于基线的 Tolerance 倍时按比例降低，
+// 否则在 sqrt(上限) 的排队余量内逐步增加
+func NewGradient(opts GradientOptions) Algorithm {
+	if opts.InitialLimit <= 0 {
+		opts.InitialLimit = 20
+	}
+	if opts.MinLimit <= 0 {
+		opts.MinLimit = 1
+	}
+	if opts.MaxLimit <= 0 {
+		opts.MaxLimit = 1000
+	}
+	if opts.Smoothing <= 0 || opts.Smoothing > 1 {
+		opts.Smoothing = 0.2
+	}
+	if opts.Tolerance < 1 {
+		opts.Tolerance = 2
+	}
+	if opts.Window <= 0 {
+		opts.Window = 600
+	}
+	return &gradient{opts: opts, limit: float64(opts.InitialLimit)}
+}
+
+type gradient struct {
+	mu      sync.Mutex
+	opts    GradientOptions
+	limit   float64
+	longRTT float64
+}
+
+func (g *gradient) Limit() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return int(g.limit)
+}
+
+func (g *gradient) Update(rtt time.Duration, inflight int, dropped bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	short := float64(rtt)
+	if short <= 0 {
+		return
+	}
+	if g.longRTT == 0 {
+		g.longRTT = short
+	} else {
+		factor := 2 / float64(g.opts.Window+1)
+		g.longRTT = g.longRTT*(1-factor) + short*factor
+	}
+	// 耗时恢复后基线快速回落，避免长期保持在过载时的高位
+	if g.longRTT/short > 2 {
+		g.longRTT *= 0.95
+	}
+
+	// 并发数远低于上限时耗时不能反映上限是否合适
+	if !dropped && float64(inflight) < g.limit/2 {
+		return
+	}
+
+	grad := math.Max(0.5, math.Min(1, g.opts.Tolerance*g.longRTT/short))
+	if dropped {
+		grad = 0.5
+	}
+	next := g.limit*grad + math.Sqrt(g.limit)
+	next = g.limit*(1-g.opts.Smoothing) + next*g.opts.Smoothing
+	g.limit = math.Max(float64(g.opts.MinLimit), math.Min(float64(g.opts.MaxLimit), next))
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
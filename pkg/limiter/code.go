@@ -0,0 +1,21 @@
+package limiter
+
+import (
+	"net/http"
+
+	"github.com/mel2oo/juice/pkg/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// ServiceBusy 并发数超出上限、请求被拒绝时的业务码，HTTP 与 gRPC 的并发限制共用
+const ServiceBusy = 10003
+
+func init() {
+	errors.MustRegister(errors.Code{
+		Code:       ServiceBusy,
+		HTTPStatus: http.StatusServiceUnavailable,
+		GRPCCode:   codes.ResourceExhausted,
+		Messages:   map[string]string{"zh": "服务繁忙，请稍后重试", "en": "Service is busy, please retry later"},
+		Retryable:  true,
+	})
+}
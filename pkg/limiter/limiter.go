@@ -0,0 +1,172 @@
+// Package limiter 服务端并发限制与过载保护：并发数达到 Algorithm 计算的上限时拒绝请求，
+// 低优先级的请求先于高优先级被拒绝
+package limiter
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Priority 请求的优先级，过载时按从低到高的顺序拒绝
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+	// PriorityCritical 用于健康检查等必须响应的请求
+	PriorityCritical
+)
+
+var priorityNames = [...]string{"low", "normal", "high", "critical"}
+
+func (p Priority) String() string {
+	if p < PriorityLow || p > PriorityCritical {
+		return "unknown"
+	}
+	return priorityNames[p]
+}
+
+// Priorities 所有优先级，从低到高
+var Priorities = []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityCritical}
+
+// defaultShares 各优先级可使用的并发上限比例
+var defaultShares = [...]float64{0.5, 0.8, 0.9, 1}
+
+type Option func(*Limiter)
+
+// WithName 设置名称，用于区分指标
+func WithName(name string) Option {
+	return func(l *Limiter) {
+		l.name = name
+	}
+}
+
+// WithShare 设置优先级 p 可使用的并发上限比例，默认 low 0.5、normal 0.8、high 0.9、critical 1
+func WithShare(p Priority, share float64) Option {
+	return func(l *Limiter) {
+		if p >= PriorityLow && p <= PriorityCritical {
+			l.shares[p] = share
+		}
+	}
+}
+
+// Limiter 并发限制器
+type Limiter struct {
+	// 64 位原子操作的字段放在最前，保证 32 位平台上的对齐
+	accepted uint64
+	shed     [len(priorityNames)]uint64
+
+	name   string
+	alg    Algorithm
+	shares [len(priorityNames)]float64
+
+	mu       sync.Mutex
+	inflight int
+
+	now func() time.Time
+}
+
+// New alg 为 nil 时使用 NewGradient 的默认配置
+func New(alg Algorithm, opts ...Option) *Limiter {
+	if alg == nil {
+		alg = NewGradient(GradientOptions{})
+	}
+	l := &Limiter{
+		name:   "default",
+		alg:    alg,
+		shares: defaultShares,
+		now:    time.Now,
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// Acquire 为优先级 p 的请求获取并发额度，返回 false 时应拒绝请求；
+// 获取成功后须在请求结束时调用 Token 的 Done 或 Drop
+func (l *Limiter) Acquire(p Priority) (*Token, bool) {
+	if p < PriorityLow {
+		p = PriorityLow
+	}
+	if p > PriorityCritical {
+		p = PriorityCritical
+	}
+
+	// 各优先级可用的并发数向下取整，至少为 1
+	limit := int(float64(l.alg.Limit()) * l.shares[p])
+	if limit < 1 {
+		limit = 1
+	}
+
+	l.mu.Lock()
+	if l.inflight >= limit {
+		l.mu.Unlock()
+		atomic.AddUint64(&l.shed[p], 1)
+		return nil, false
+	}
+	l.inflight++
+	inflight := l.inflight
+	l.mu.Unlock()
+
+	atomic.AddUint64(&l.accepted, 1)
+	return &Token{limiter: l, start: l.now(), inflight: inflight}, true
+}
+
+func (l *Limiter) release(t *Token, dropped bool) {
+	l.mu.Lock()
+	l.inflight--
+	l.mu.Unlock()
+
+	l.alg.Update(l.now().Sub(t.start), t.inflight, dropped)
+}
+
+// Name WithName 设置的名称，默认为 default
+func (l *Limiter) Name() string {
+	return l.name
+}
+
+// Stats 限制器的状态
+type Stats struct {
+	Limit    int
+	Inflight int
+	Accepted uint64
+	Shed     map[Priority]uint64 // 各优先级被拒绝的请求数
+}
+
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	inflight := l.inflight
+	l.mu.Unlock()
+
+	s := Stats{
+		Limit:    l.alg.Limit(),
+		Inflight: inflight,
+		Accepted: atomic.LoadUint64(&l.accepted),
+		Shed:     make(map[Priority]uint64, len(Priorities)),
+	}
+	for _, p := range Priorities {
+		s.Shed[p] = atomic.LoadUint64(&l.shed[p])
+	}
+	return s
+}
+
+// Token 一次获取的并发额度
+type Token struct {
+	limiter  *Limiter
+	start    time.Time
+	inflight int
+	once     sync.Once
+}
+
+// Done 请求正常结束，耗时用于调整并发上限
+func (t *Token) Done() {
+	t.once.Do(func() { t.limiter.release(t, false) })
+}
+
+// Drop 请求因超时或过载失败，自适应算法会降低并发上限
+func (t *Token) Drop() {
+	t.once.Do(func() { t.limiter.release(t, true) })
+}
@@ -0,0 +1,80 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterPriority(t *testing.T) {
+	l := New(Fixed(10))
+
+	var tokens []*Token
+	for i := 0; i < 8; i++ {
+		token, ok := l.Acquire(PriorityNormal)
+		if !ok {
+			t.Fatalf("request %d should be accepted", i)
+		}
+		tokens = append(tokens, token)
+	}
+
+	// normal 最多使用 80%，low 最多 50%，critical 可使用全部
+	if _, ok := l.Acquire(PriorityNormal); ok {
+		t.Fatal("normal request should be shed")
+	}
+	if _, ok := l.Acquire(PriorityLow); ok {
+		t.Fatal("low request should be shed")
+	}
+	if _, ok := l.Acquire(PriorityCritical); !ok {
+		t.Fatal("critical request should be accepted")
+	}
+
+	s := l.Stats()
+	if s.Limit != 10 || s.Inflight != 9 || s.Accepted != 9 || s.Shed[PriorityNormal] != 1 || s.Shed[PriorityLow] != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+
+	tokens[0].Done()
+	tokens[0].Done()
+	if s := l.Stats(); s.Inflight != 8 {
+		t.Fatalf("token should be released once, inflight %d", s.Inflight)
+	}
+}
+
+func TestAIMD(t *testing.T) {
+	alg := NewAIMD(AIMDOptions{InitialLimit: 10, MaxLimit: 11, Timeout: time.Second})
+
+	alg.Update(10*time.Millisecond, 5, false)
+	alg.Update(10*time.Millisecond, 5, false)
+	if alg.Limit() != 11 {
+		t.Fatalf("limit should increase up to max, got %d", alg.Limit())
+	}
+
+	alg.Update(2*time.Second, 5, false)
+	if alg.Limit() != 9 {
+		t.Fatalf("limit should back off on timeout, got %d", alg.Limit())
+	}
+
+	alg.Update(10*time.Millisecond, 1, false)
+	if alg.Limit() != 9 {
+		t.Fatalf("limit should not increase when underused, got %d", alg.Limit())
+	}
+}
+
+func TestGradient(t *testing.T) {
+	alg := NewGradient(GradientOptions{InitialLimit: 100})
+
+	for i := 0; i < 20; i++ {
+		alg.Update(10*time.Millisecond, 100, false)
+	}
+	grown := alg.Limit()
+	if grown <= 100 {
+		t.Fatalf("limit should grow with stable latency, got %d", grown)
+	}
+
+	for i := 0; i < 20; i++ {
+		alg.Update(100*time.Millisecond, grown, false)
+	}
+	if alg.Limit() >= grown {
+		t.Fatalf("limit should decrease when latency grows, got %d (was %d)", alg.Limit(), grown)
+	}
+}
@@ -0,0 +1,93 @@
+// Package metrics 以 prometheus 指标暴露 limiter 的并发上限、并发数及被拒绝的请求数
+package metrics
+
+import (
+	"sync"
+
+	"github.com/mel2oo/juice/pkg/limiter"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "switch"
+	subsystem = "juice"
+)
+
+var (
+	limitDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "limiter_limit"),
+		"current concurrency limit",
+		[]string{"limiter"}, nil,
+	)
+	inflightDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "limiter_inflight"),
+		"requests currently in flight",
+		[]string{"limiter"}, nil,
+	)
+	acceptedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "limiter_accepted_total"),
+		"requests accepted by the concurrency limiter",
+		[]string{"limiter"}, nil,
+	)
+	shedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "limiter_shed_total"),
+		"requests rejected by the concurrency limiter",
+		[]string{"limiter", "priority"}, nil,
+	)
+)
+
+type collector struct {
+	mu       sync.RWMutex
+	limiters []*limiter.Limiter
+}
+
+// NewCollector 返回采集 limiters 指标的 Collector，limiters 以 Name 区分
+func NewCollector(limiters ...*limiter.Limiter) prometheus.Collector {
+	return &collector{limiters: limiters}
+}
+
+var (
+	registerMu sync.Mutex
+	registered *collector
+)
+
+// Register 将 limiters 加入注册到 prometheus.DefaultRegisterer 的 Collector，可多次调用
+func Register(limiters ...*limiter.Limiter) error {
+	registerMu.Lock()
+	defer registerMu.Unlock()
+
+	if registered == nil {
+		c := &collector{}
+		if err := prometheus.Register(c); err != nil {
+			return err
+		}
+		registered = c
+	}
+
+	registered.mu.Lock()
+	registered.limiters = append(registered.limiters, limiters...)
+	registered.mu.Unlock()
+	return nil
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- limitDesc
+	ch <- inflightDesc
+	ch <- acceptedDesc
+	ch <- shedDesc
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, l := range c.limiters {
+		s := l.Stats()
+		ch <- prometheus.MustNewConstMetric(limitDesc, prometheus.GaugeValue, float64(s.Limit), l.Name())
+		ch <- prometheus.MustNewConstMetric(inflightDesc, prometheus.GaugeValue, float64(s.Inflight), l.Name())
+		ch <- prometheus.MustNewConstMetric(acceptedDesc, prometheus.CounterValue, float64(s.Accepted), l.Name())
+		for _, p := range limiter.Priorities {
+			ch <- prometheus.MustNewConstMetric(shedDesc, prometheus.CounterValue, float64(s.Shed[p]), l.Name(), p.String())
+		}
+	}
+}
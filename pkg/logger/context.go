@@ -0,0 +1,22 @@
+package logger
+
+import "context"
+
+type ctxMarker struct{}
+
+var ctxMarkerKey = &ctxMarker{}
+
+// ToContext 将 Logger 放入 ctx，通常为附带了 trace_id 等请求字段的子 Logger
+func ToContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, ctxMarkerKey, l)
+}
+
+// FromContext 取出 ToContext 放入的 Logger，不存在时返回不输出任何内容的 Logger
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxMarkerKey).(Logger); ok && l != nil {
+			return l
+		}
+	}
+	return nop
+}
@@ -2,6 +2,7 @@ package dlog
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/pkg/logger/writer"
 )
 
 var DefaultLogger logger.Logger = NewDefaultLogger(
@@ -19,8 +21,12 @@ var DefaultLogger logger.Logger = NewDefaultLogger(
 )
 
 type defaultLogger struct {
-	log  *log.Logger
-	opts *logger.Options
+	log    *log.Logger
+	opts   *logger.Options
+	level  logger.AtomicLevel
+	fields []logger.Field
+	// file 生产模式下的日志文件，开发模式输出到标准输出时为 nil
+	file io.WriteCloser
 }
 
 func NewDefaultLogger(opts ...logger.Option) logger.Logger {
@@ -40,8 +46,9 @@ func NewDefaultLogger(opts ...logger.Option) logger.Logger {
 func newDevelopment(opts *logger.Options) logger.Logger {
 	w := os.Stdout
 	return &defaultLogger{
-		log.New(w, opts.Prefix, log.LstdFlags),
-		opts,
+		log:   log.New(w, opts.Prefix, log.LstdFlags),
+		opts:  opts,
+		level: logger.NewAtomicLevel(opts.Level),
 	}
 }
 
@@ -50,14 +57,13 @@ func newProduction(opts *logger.Options) logger.Logger {
 		opts.OutputPath += "/"
 	}
 
-	logFile, err := os.OpenFile(opts.OutputPath+opts.OutputName, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0766)
-	if err != nil {
-		panic(err)
-	}
+	logFile := writer.NewFile(opts, opts.OutputPath+opts.OutputName)
 
 	return &defaultLogger{
-		log.New(logFile, opts.Prefix, log.Ldate),
-		opts,
+		log:   log.New(logFile, opts.Prefix, log.Ldate),
+		opts:  opts,
+		level: logger.NewAtomicLevel(opts.Level),
+		file:  logFile,
 	}
 }
 
@@ -65,73 +71,135 @@ func (d *defaultLogger) Type() interface{} {
 	return d
 }
 
-func (d *defaultLogger) Log(level logger.Level, msg string) {
-	l := level.String()
-	if d.opts.DisableCaller {
-		d.log.Printf("%s %v\n", l, msg)
-	} else {
-		if _, file, line, ok := runtime.Caller(2); ok {
-			caller := fmt.Sprintf("%s:%d", filepath.Base(file), line)
-			d.log.Printf("%s %s %v\n", l, caller, msg)
-		}
+func (d *defaultLogger) With(fields ...logger.Field) logger.Logger {
+	return &defaultLogger{
+		log:    d.log,
+		opts:   d.opts,
+		level:  d.level,
+		file:   d.file,
+		fields: append(d.fields[:len(d.fields):len(d.fields)], fields...),
 	}
 }
 
+func (d *defaultLogger) Sync() error {
+	if s, ok := d.file.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+func (d *defaultLogger) Close() error {
+	if d.file == nil {
+		return nil
+	}
+	return d.file.Close()
+}
+
+func (d *defaultLogger) SetLevel(level logger.Level) {
+	d.level.SetLevel(level)
+}
+
+func (d *defaultLogger) GetLevel() logger.Level {
+	return d.level.Level()
+}
+
+func (d *defaultLogger) Log(level logger.Level, msg string) {
+	d.output(level, msg, nil)
+}
+
 func (d *defaultLogger) Logf(level logger.Level, format string, v ...interface{}) {
+	d.output(level, fmt.Sprintf(format, v...), nil)
+}
+
+// output 统一输出，调用层级固定为 用户代码 -> 导出方法 -> output
+func (d *defaultLogger) output(level logger.Level, msg string, fields []logger.Field) {
+	if !d.level.Enabled(level) {
+		return
+	}
+
+	if len(d.fields) > 0 || len(fields) > 0 {
+		var b strings.Builder
+		b.WriteString(msg)
+		for _, f := range d.fields {
+			b.WriteString(" ")
+			b.WriteString(f.String())
+		}
+		for _, f := range fields {
+			b.WriteString(" ")
+			b.WriteString(f.String())
+		}
+		msg = b.String()
+	}
+
 	l := level.String()
-	m := fmt.Sprintf(format, v...)
 	if d.opts.DisableCaller {
-		d.log.Printf("%s %v\n", l, m)
+		d.log.Printf("%s %v\n", l, msg)
 	} else {
 		if _, file, line, ok := runtime.Caller(2); ok {
 			caller := fmt.Sprintf("%s:%d", filepath.Base(file), line)
-			d.log.Printf("%s %s %v\n", l, caller, m)
+			d.log.Printf("%s %s %v\n", l, caller, msg)
 		}
 	}
 }
 
 func (d *defaultLogger) Debug(v ...interface{}) {
-	d.Log(logger.DebugLevel, fmt.Sprint(v...))
+	d.output(logger.DebugLevel, fmt.Sprint(v...), nil)
 }
 
 func (d *defaultLogger) Debugf(format string, args ...interface{}) {
-	d.Log(logger.DebugLevel, fmt.Sprintf(format, args...))
+	d.output(logger.DebugLevel, fmt.Sprintf(format, args...), nil)
 }
 
 func (d *defaultLogger) Info(v ...interface{}) {
-	d.Log(logger.InfoLevel, fmt.Sprint(v...))
+	d.output(logger.InfoLevel, fmt.Sprint(v...), nil)
 }
 
 func (d *defaultLogger) Infof(format string, args ...interface{}) {
-	d.Log(logger.InfoLevel, fmt.Sprintf(format, args...))
+	d.output(logger.InfoLevel, fmt.Sprintf(format, args...), nil)
 }
 
 func (d *defaultLogger) Warn(v ...interface{}) {
-	d.Log(logger.WarnLevel, fmt.Sprint(v...))
+	d.output(logger.WarnLevel, fmt.Sprint(v...), nil)
 }
 
 func (d *defaultLogger) Warnf(format string, args ...interface{}) {
-	d.Log(logger.WarnLevel, fmt.Sprintf(format, args...))
+	d.output(logger.WarnLevel, fmt.Sprintf(format, args...), nil)
 }
 
 func (d *defaultLogger) Error(v ...interface{}) {
-	d.Log(logger.ErrorLevel, fmt.Sprint(v...))
+	d.output(logger.ErrorLevel, fmt.Sprint(v...), nil)
 }
 
 func (d *defaultLogger) Errorf(format string, args ...interface{}) {
-	d.Log(logger.ErrorLevel, fmt.Sprintf(format, args...))
+	d.output(logger.ErrorLevel, fmt.Sprintf(format, args...), nil)
 }
 
 func (d *defaultLogger) DPanic(v ...interface{}) {
-	d.Log(logger.DPanicLevel, fmt.Sprint(v...))
+	d.output(logger.DPanicLevel, fmt.Sprint(v...), nil)
 }
 
 func (d *defaultLogger) Panic(v ...interface{}) {
-	d.Log(logger.PanicLevel, fmt.Sprint(v...))
+	d.output(logger.PanicLevel, fmt.Sprint(v...), nil)
 }
 
 func (d *defaultLogger) Fatal(v ...interface{}) {
-	d.Log(logger.FatalLevel, fmt.Sprint(v...))
+	d.output(logger.FatalLevel, fmt.Sprint(v...), nil)
+}
+
+func (d *defaultLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	d.output(logger.DebugLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
+
+func (d *defaultLogger) Infow(msg string, keysAndValues ...interface{}) {
+	d.output(logger.InfoLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
+
+func (d *defaultLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	d.output(logger.WarnLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
+
+func (d *defaultLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	d.output(logger.ErrorLevel, msg, logger.KeysAndValues(keysAndValues...))
 }
 
 func (d *defaultLogger) String() string {
@@ -1,6 +1,9 @@
 package dlog
 
 import (
+	"bytes"
+	"errors"
+	stdlog "log"
 	"testing"
 
 	"github.com/mel2oo/juice/pkg/logger"
@@ -28,3 +31,39 @@ func Test_DisableCaller(t *testing.T) {
 	)
 	log.Info("hello world")
 }
+
+func Test_StructuredFields(t *testing.T) {
+	buf := new(bytes.Buffer)
+	var log logger.Logger = &defaultLogger{
+		log:   stdlog.New(buf, "", 0),
+		opts:  &logger.Options{DisableCaller: true},
+		level: logger.NewAtomicLevel(logger.DebugLevel),
+	}
+
+	log.With(logger.String("trace_id", "t1")).Infow("hello", "user_id", 1, logger.Error(errors.New("boom")))
+	if got := buf.String(); got != "INFO hello trace_id=t1 user_id=1 error=boom\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+}
+
+func Test_LevelFiltering(t *testing.T) {
+	buf := new(bytes.Buffer)
+	var log logger.Logger = &defaultLogger{
+		log:   stdlog.New(buf, "", 0),
+		opts:  &logger.Options{DisableCaller: true},
+		level: logger.NewAtomicLevel(logger.WarnLevel),
+	}
+	child := log.With(logger.String("k", "v"))
+
+	child.Info("dropped")
+	child.Warn("kept")
+	log.SetLevel(logger.DebugLevel)
+	child.Debug("debug")
+
+	if got := buf.String(); got != "WARN kept k=v\nDEBUG debug k=v\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+	if child.GetLevel() != logger.DebugLevel {
+		t.Fatalf("unexpected level %v", child.GetLevel())
+	}
+}
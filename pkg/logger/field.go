@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+// Field 与具体日志实现无关的结构化字段
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+func (f Field) String() string {
+	return fmt.Sprintf("%s=%v", f.Key, f.Value)
+}
+
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Error 以 error 为 key 记录 err
+func Error(err error) Field {
+	return NamedError("error", err)
+}
+
+func NamedError(key string, err error) Field {
+	return Field{Key: key, Value: err}
+}
+
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// KeysAndValues 将 Debugw 等方法的 key/value 参数转换为 Field，参数中可以直接混用 Field
+func KeysAndValues(args ...interface{}) []Field {
+	fields := make([]Field, 0, len(args)/2+1)
+	for i := 0; i < len(args); i++ {
+		if f, ok := args[i].(Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+
+		if i == len(args)-1 {
+			fields = append(fields, Any("!BADKEY", args[i]))
+			break
+		}
+
+		key, ok := args[i].(string)
+		if !ok {
+			key = fmt.Sprint(args[i])
+		}
+		fields = append(fields, Any(key, args[i+1]))
+		i++
+	}
+	return fields
+}
@@ -1,5 +1,11 @@
 package logger
 
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
 type Level int8
 
 const (
@@ -32,3 +38,65 @@ func (l Level) String() string {
 		return ""
 	}
 }
+
+// LevelPtr 返回 l 的指针，用于设置 Sink.Level
+func LevelPtr(l Level) *Level {
+	return &l
+}
+
+// ParseLevel 解析 debug、info 等级别名称，不区分大小写
+func ParseLevel(s string) (Level, error) {
+	var l Level
+	err := l.UnmarshalText([]byte(s))
+	return l, err
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(l.String())), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	switch strings.ToUpper(string(text)) {
+	case "DEBUG":
+		*l = DebugLevel
+	case "INFO", "":
+		*l = InfoLevel
+	case "WARN", "WARNING":
+		*l = WarnLevel
+	case "ERROR":
+		*l = ErrorLevel
+	case "DPANIC":
+		*l = DPanicLevel
+	case "PANIC":
+		*l = PanicLevel
+	case "FATAL":
+		*l = FatalLevel
+	default:
+		return fmt.Errorf("logger: unrecognized level %q", text)
+	}
+	return nil
+}
+
+// AtomicLevel 可并发修改的级别，复制后仍指向同一级别，
+// 供 Logger 实现在 With 派生的子 Logger 间共享级别
+type AtomicLevel struct {
+	l *int32
+}
+
+func NewAtomicLevel(l Level) AtomicLevel {
+	v := int32(l)
+	return AtomicLevel{l: &v}
+}
+
+func (a AtomicLevel) Level() Level {
+	return Level(atomic.LoadInt32(a.l))
+}
+
+func (a AtomicLevel) SetLevel(l Level) {
+	atomic.StoreInt32(a.l, int32(l))
+}
+
+// Enabled 判断 l 级别的日志是否需要输出
+func (a AtomicLevel) Enabled(l Level) bool {
+	return l >= a.Level()
+}
@@ -4,8 +4,20 @@ type Logger interface {
 	String() string
 	Type() interface{}
 
+	// With 返回附带 fields 的子 Logger，不影响原 Logger
+	With(fields ...Field) Logger
+
 	Log(level Level, v string)
 
+	// SetLevel 运行时修改最低输出级别，With 派生的子 Logger 共享同一级别
+	SetLevel(level Level)
+	GetLevel() Level
+
+	// Sync 将缓冲的日志写入输出
+	Sync() error
+	// Close 写完缓冲的日志并关闭文件等输出，With 派生的子 Logger 共享同一输出，关闭后不应再使用
+	Close() error
+
 	Debug(v ...interface{})
 	Debugf(format string, args ...interface{})
 	Info(v ...interface{})
@@ -17,4 +29,10 @@ type Logger interface {
 	DPanic(v ...interface{})
 	Panic(v ...interface{})
 	Fatal(v ...interface{})
+
+	// Debugw 等方法以 key/value 形式记录结构化字段，如 Infow("msg", "user_id", 1, logger.Error(err))
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
 }
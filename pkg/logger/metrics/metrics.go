@@ -0,0 +1,38 @@
+// Package metrics 以 prometheus 指标暴露被丢弃的日志条数，引入即注册
+package metrics
+
+import (
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "switch"
+	subsystem = "juice"
+)
+
+// metricsLoggerDropped 按原因(sampled、rate_limited、queue_full)统计被丢弃的日志条数 计数器（Counter）
+var metricsLoggerDropped = []prometheus.Collector{
+	newDroppedCounter(logger.DropSampled),
+	newDroppedCounter(logger.DropRateLimited),
+	newDroppedCounter(logger.DropQueueFull),
+}
+
+func newDroppedCounter(reason string) prometheus.CounterFunc {
+	return prometheus.NewCounterFunc(
+		prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "logger_dropped_total",
+			Help:        "log entries dropped by sampling, rate limiting or a full async queue",
+			ConstLabels: prometheus.Labels{"reason": reason},
+		},
+		func() float64 {
+			return float64(logger.DroppedCount(reason))
+		},
+	)
+}
+
+func init() {
+	prometheus.MustRegister(metricsLoggerDropped...)
+}
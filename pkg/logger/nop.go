@@ -0,0 +1,34 @@
+package logger
+
+var nop Logger = nopLogger{}
+
+// NewNop 返回丢弃所有日志的 Logger
+func NewNop() Logger {
+	return nop
+}
+
+type nopLogger struct{}
+
+func (nopLogger) String() string                { return "nop" }
+func (nopLogger) Type() interface{}             { return nil }
+func (n nopLogger) With(...Field) Logger        { return n }
+func (nopLogger) Log(Level, string)             {}
+func (nopLogger) SetLevel(Level)                {}
+func (nopLogger) GetLevel() Level               { return FatalLevel }
+func (nopLogger) Sync() error                   { return nil }
+func (nopLogger) Close() error                  { return nil }
+func (nopLogger) Debug(...interface{})          {}
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Info(...interface{})           {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Warn(...interface{})           {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+func (nopLogger) Error(...interface{})          {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+func (nopLogger) DPanic(...interface{})         {}
+func (nopLogger) Panic(...interface{})          {}
+func (nopLogger) Fatal(...interface{})          {}
+func (nopLogger) Debugw(string, ...interface{}) {}
+func (nopLogger) Infow(string, ...interface{})  {}
+func (nopLogger) Warnw(string, ...interface{})  {}
+func (nopLogger) Errorw(string, ...interface{}) {}
@@ -0,0 +1,272 @@
+// Package observer 提供记录日志到内存的 Logger，用于在测试中断言 handler、拦截器输出的日志
+package observer
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/logger"
+)
+
+// Entry 一条被记录的日志
+type Entry struct {
+	Time    time.Time
+	Level   logger.Level
+	Message string
+	// Fields 包含 With 附带的字段与调用时传入的字段
+	Fields []logger.Field
+	// Caller 调用位置，如 handler.go:42
+	Caller string
+}
+
+// Field 返回 key 对应的字段值，同名字段取最后一个
+func (e Entry) Field(key string) (interface{}, bool) {
+	for i := len(e.Fields) - 1; i >= 0; i-- {
+		if e.Fields[i].Key == key {
+			return e.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// ContextMap 以 map 形式返回所有字段
+func (e Entry) ContextMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(e.Fields))
+	for _, f := range e.Fields {
+		m[f.Key] = f.Value
+	}
+	return m
+}
+
+// ObservedLogs 并发安全的日志记录集合
+type ObservedLogs struct {
+	mu   sync.RWMutex
+	logs []Entry
+}
+
+// Len 返回记录的条数
+func (o *ObservedLogs) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return len(o.logs)
+}
+
+// All 返回所有记录的副本
+func (o *ObservedLogs) All() []Entry {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	ret := make([]Entry, len(o.logs))
+	copy(ret, o.logs)
+	return ret
+}
+
+// TakeAll 返回并清空所有记录
+func (o *ObservedLogs) TakeAll() []Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ret := o.logs
+	o.logs = nil
+	return ret
+}
+
+// Filter 返回满足 keep 的记录组成的新集合
+func (o *ObservedLogs) Filter(keep func(Entry) bool) *ObservedLogs {
+	var filtered []Entry
+	for _, e := range o.All() {
+		if keep(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	return &ObservedLogs{logs: filtered}
+}
+
+// FilterLevel 过滤出级别为 level 的记录
+func (o *ObservedLogs) FilterLevel(level logger.Level) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		return e.Level == level
+	})
+}
+
+// FilterLevelAtLeast 过滤出级别不低于 level 的记录
+func (o *ObservedLogs) FilterLevelAtLeast(level logger.Level) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		return e.Level >= level
+	})
+}
+
+// FilterMessage 过滤出内容等于 msg 的记录
+func (o *ObservedLogs) FilterMessage(msg string) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		return e.Message == msg
+	})
+}
+
+// FilterMessageSnippet 过滤出内容包含 snippet 的记录
+func (o *ObservedLogs) FilterMessageSnippet(snippet string) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		return strings.Contains(e.Message, snippet)
+	})
+}
+
+// FilterField 过滤出含有字段 field 且值相等(按 fmt 格式化比较)的记录
+func (o *ObservedLogs) FilterField(field logger.Field) *ObservedLogs {
+	want := fmt.Sprint(field.Value)
+	return o.Filter(func(e Entry) bool {
+		v, ok := e.Field(field.Key)
+		return ok && fmt.Sprint(v) == want
+	})
+}
+
+// FilterFieldKey 过滤出含有名为 key 的字段的记录
+func (o *ObservedLogs) FilterFieldKey(key string) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		_, ok := e.Field(key)
+		return ok
+	})
+}
+
+func (o *ObservedLogs) add(e Entry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.logs = append(o.logs, e)
+}
+
+// New 返回记录不低于 level 的日志的 Logger 以及对应的记录集合，
+// 可以用于 http.WithLogger、grpc.Logger 等任何接受 logger.Logger 的地方；
+// Panic 与 Fatal 只记录，不会 panic 或退出
+func New(level logger.Level) (logger.Logger, *ObservedLogs) {
+	logs := new(ObservedLogs)
+	return &observedLogger{
+		logs:  logs,
+		level: logger.NewAtomicLevel(level),
+	}, logs
+}
+
+type observedLogger struct {
+	logs   *ObservedLogs
+	level  logger.AtomicLevel
+	fields []logger.Field
+}
+
+// record 调用层级固定为 用户代码 -> 导出方法 -> record
+func (o *observedLogger) record(level logger.Level, msg string, fields []logger.Field) {
+	if !o.level.Enabled(level) {
+		return
+	}
+
+	e := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: msg,
+		Fields:  append(o.fields[:len(o.fields):len(o.fields)], fields...),
+	}
+	if _, file, line, ok := runtime.Caller(2); ok {
+		e.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	o.logs.add(e)
+}
+
+func (o *observedLogger) String() string {
+	return "observer"
+}
+
+// Type 返回 *ObservedLogs
+func (o *observedLogger) Type() interface{} {
+	return o.logs
+}
+
+func (o *observedLogger) With(fields ...logger.Field) logger.Logger {
+	return &observedLogger{
+		logs:   o.logs,
+		level:  o.level,
+		fields: append(o.fields[:len(o.fields):len(o.fields)], fields...),
+	}
+}
+
+func (o *observedLogger) SetLevel(level logger.Level) {
+	o.level.SetLevel(level)
+}
+
+func (o *observedLogger) GetLevel() logger.Level {
+	return o.level.Level()
+}
+
+func (o *observedLogger) Sync() error {
+	return nil
+}
+
+func (o *observedLogger) Close() error {
+	return nil
+}
+
+func (o *observedLogger) Log(level logger.Level, msg string) {
+	o.record(level, msg, nil)
+}
+
+func (o *observedLogger) Debug(v ...interface{}) {
+	o.record(logger.DebugLevel, fmt.Sprint(v...), nil)
+}
+
+func (o *observedLogger) Debugf(format string, args ...interface{}) {
+	o.record(logger.DebugLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (o *observedLogger) Info(v ...interface{}) {
+	o.record(logger.InfoLevel, fmt.Sprint(v...), nil)
+}
+
+func (o *observedLogger) Infof(format string, args ...interface{}) {
+	o.record(logger.InfoLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (o *observedLogger) Warn(v ...interface{}) {
+	o.record(logger.WarnLevel, fmt.Sprint(v...), nil)
+}
+
+func (o *observedLogger) Warnf(format string, args ...interface{}) {
+	o.record(logger.WarnLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (o *observedLogger) Error(v ...interface{}) {
+	o.record(logger.ErrorLevel, fmt.Sprint(v...), nil)
+}
+
+func (o *observedLogger) Errorf(format string, args ...interface{}) {
+	o.record(logger.ErrorLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (o *observedLogger) DPanic(v ...interface{}) {
+	o.record(logger.DPanicLevel, fmt.Sprint(v...), nil)
+}
+
+func (o *observedLogger) Panic(v ...interface{}) {
+	o.record(logger.PanicLevel, fmt.Sprint(v...), nil)
+}
+
+func (o *observedLogger) Fatal(v ...interface{}) {
+	o.record(logger.FatalLevel, fmt.Sprint(v...), nil)
+}
+
+func (o *observedLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	o.record(logger.DebugLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
+
+func (o *observedLogger) Infow(msg string, keysAndValues ...interface{}) {
+	o.record(logger.InfoLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
+
+func (o *observedLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	o.record(logger.WarnLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
+
+func (o *observedLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	o.record(logger.ErrorLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
@@ -0,0 +1,44 @@
+package observer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mel2oo/juice/pkg/logger"
+)
+
+func TestObserver(t *testing.T) {
+	log, logs := New(logger.InfoLevel)
+
+	log.Debug("dropped")
+	log.With(logger.String("trace_id", "t1")).Infow("request", "user_id", 1)
+	log.Errorw("failed", logger.Error(errors.New("boom")))
+	log.Warnf("retry %d", 2)
+
+	if logs.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", logs.Len())
+	}
+
+	entries := logs.FilterField(logger.String("trace_id", "t1")).All()
+	if len(entries) != 1 || entries[0].Message != "request" || entries[0].ContextMap()["user_id"] != 1 {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if !strings.HasPrefix(entries[0].Caller, "observer_test.go:") {
+		t.Fatalf("unexpected caller %s", entries[0].Caller)
+	}
+
+	if n := logs.FilterLevelAtLeast(logger.WarnLevel).Len(); n != 2 {
+		t.Fatalf("expected 2 warn+ entries, got %d", n)
+	}
+	if n := logs.FilterMessageSnippet("retry").FilterLevel(logger.WarnLevel).Len(); n != 1 {
+		t.Fatalf("expected 1 retry entry, got %d", n)
+	}
+	if n := logs.FilterFieldKey("error").Len(); n != 1 {
+		t.Fatalf("expected 1 error entry, got %d", n)
+	}
+
+	if all := logs.TakeAll(); len(all) != 3 || logs.Len() != 0 {
+		t.Fatalf("TakeAll returned %d, %d left", len(all), logs.Len())
+	}
+}
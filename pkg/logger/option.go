@@ -2,10 +2,48 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"time"
 )
 
+// 日志编码格式
+const (
+	EncodingConsole = "console"
+	EncodingJSON    = "json"
+	EncodingLogfmt  = "logfmt"
+)
+
+// Sink.Output 的特殊取值
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+// 按时间滚动日志文件的周期
+const (
+	RotationDaily  = "daily"
+	RotationHourly = "hourly"
+)
+
+// Sink 一个日志输出目标，配置了 Sink 时不再使用默认输出
+type Sink struct {
+	// Output 为 stdout、stderr 或文件名，相对路径基于 OutputPath，文件按 MaxSize 或 Rotation 等参数滚动，
+	// 按时间滚动时文件名可以含有 %Y-%m-%d 等占位符；
+	// 也可以是 syslog(RFC 5424)或 journald 地址，此时忽略 Encoding，字段输出为 structured data 或 journal 字段：
+	// syslog+udp://127.0.0.1:514?app=api&facility=local0、syslog+tcp://host:601、syslog+unixgram:///dev/log、
+	// journald 或 journald:///run/systemd/journal/socket
+	Output string
+	// Writer 非空时直接写入 Writer，忽略 Output
+	Writer io.Writer
+	// Level 该输出的最低级别，与 Options.Level 同时生效；为 nil 时只使用 Options.Level，
+	// 运行时通过 SetLevel 修改级别同样作用于该输出
+	Level *Level
+	// Encoding 为空时使用 Options.Encoding
+	Encoding string
+}
+
 type Option func(*Options)
 
 type Options struct {
@@ -20,6 +58,32 @@ type Options struct {
 	MaxBackups        int
 	MaxAge            int
 	Compress          bool
+	Encoding          string
+	TimeKey           string
+	LevelKey          string
+	CallerKey         string
+	MessageKey        string
+	Sinks             []Sink
+	Rotation          string
+	FilePattern       string
+	AsyncQueueSize    int
+	Sampling          *Sampling
+	RateLimits        map[string]RateLimit
+	AlwaysLogLevel    Level
+}
+
+// Sampling 采样配置：每个 Tick 内同一级别同一消息的前 Initial 条全部输出，之后每 Thereafter 条输出一条，
+// Thereafter 为 0 时超出 Initial 的全部丢弃
+type Sampling struct {
+	Tick       time.Duration
+	Initial    int
+	Thereafter int
+}
+
+// RateLimit 单条消息的限速：平均每 Every 输出一条，允许突发 Burst 条
+type RateLimit struct {
+	Every time.Duration
+	Burst int
 }
 
 func NewOptions() *Options {
@@ -37,6 +101,8 @@ func NewOptions() *Options {
 		MaxBackups:        10,
 		MaxAge:            7,
 		Compress:          false,
+		Encoding:          EncodingConsole,
+		AlwaysLogLevel:    ErrorLevel,
 	}
 }
 
@@ -105,3 +171,94 @@ func WithEnableCompress() Option {
 		o.Compress = true
 	}
 }
+
+// WithEncoding 设置日志编码格式：console、json 或 logfmt
+func WithEncoding(encoding string) Option {
+	return func(o *Options) {
+		o.Encoding = encoding
+	}
+}
+
+// WithTimeKey 设置结构化输出中时间字段的 key，为空时使用实现的默认值
+func WithTimeKey(key string) Option {
+	return func(o *Options) {
+		o.TimeKey = key
+	}
+}
+
+// WithLevelKey 设置结构化输出中级别字段的 key
+func WithLevelKey(key string) Option {
+	return func(o *Options) {
+		o.LevelKey = key
+	}
+}
+
+// WithCallerKey 设置结构化输出中调用位置字段的 key
+func WithCallerKey(key string) Option {
+	return func(o *Options) {
+		o.CallerKey = key
+	}
+}
+
+// WithMessageKey 设置结构化输出中日志内容字段的 key
+func WithMessageKey(key string) Option {
+	return func(o *Options) {
+		o.MessageKey = key
+	}
+}
+
+// WithSink 追加一个输出目标，可多次调用同时输出到多处，如标准输出加单独的错误日志文件
+func WithSink(s Sink) Option {
+	return func(o *Options) {
+		o.Sinks = append(o.Sinks, s)
+	}
+}
+
+// WithRotation 按天(daily)或小时(hourly)滚动日志文件，旧文件同样按 MaxBackups 与 MaxAge 清理
+func WithRotation(rotation string) Option {
+	return func(o *Options) {
+		o.Rotation = rotation
+	}
+}
+
+// WithFilePattern 设置按时间滚动时默认输出的文件名，支持 %Y %m %d %H %M，如 "app.%Y%m%d%H.log"，
+// 相对路径基于 OutputPath
+func WithFilePattern(pattern string) Option {
+	return func(o *Options) {
+		o.FilePattern = pattern
+	}
+}
+
+// WithAsync 日志文件改为异步写入，queueSize 为有界队列长度，队列已满时丢弃日志并计数
+func WithAsync(queueSize int) Option {
+	return func(o *Options) {
+		o.AsyncQueueSize = queueSize
+	}
+}
+
+// WithSampling 开启采样(zap 实现)，见 Sampling；tick 为 0 时取 1 秒
+func WithSampling(tick time.Duration, initial, thereafter int) Option {
+	return func(o *Options) {
+		if tick <= 0 {
+			tick = time.Second
+		}
+		o.Sampling = &Sampling{Tick: tick, Initial: initial, Thereafter: thereafter}
+	}
+}
+
+// WithRateLimit 对内容为 msg 的日志限速(zap 实现)，用于压制高频的噪声日志
+func WithRateLimit(msg string, every time.Duration, burst int) Option {
+	return func(o *Options) {
+		if o.RateLimits == nil {
+			o.RateLimits = make(map[string]RateLimit)
+		}
+		o.RateLimits[msg] = RateLimit{Every: every, Burst: burst}
+	}
+}
+
+// WithAlwaysLogLevel 不低于 level 的日志不受采样与限速影响，默认为 ErrorLevel
+func WithAlwaysLogLevel(level Level) Option {
+	return func(o *Options) {
+		o.AlwaysLogLevel = level
+	}
+}
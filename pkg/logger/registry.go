@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUnknownLogger 未注册的 Logger 名称
+var ErrUnknownLogger = errors.New("logger: unknown logger")
+
+// DefaultRegistry 默认的 Logger 注册表，/system/loggers 等管理接口默认使用
+var DefaultRegistry = NewRegistry()
+
+// Register 将 l 以 name 注册到 DefaultRegistry
+func Register(name string, l Logger) {
+	DefaultRegistry.Register(name, l)
+}
+
+// LevelInfo 已注册 Logger 的级别信息
+type LevelInfo struct {
+	Name  string `json:"name"`
+	Level Level  `json:"level"`
+	// RevertAt 非空时表示到期后级别恢复为 RevertLevel
+	RevertAt    *time.Time `json:"revert_at,omitempty"`
+	RevertLevel *Level     `json:"revert_level,omitempty"`
+}
+
+// Registry 按名称管理 Logger，支持运行时修改单个 Logger 的级别并定时恢复
+type Registry struct {
+	mu      sync.Mutex
+	loggers map[string]Logger
+	reverts map[string]*revert
+}
+
+type revert struct {
+	timer *time.Timer
+	at    time.Time
+	level Level
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		loggers: make(map[string]Logger),
+		reverts: make(map[string]*revert),
+	}
+}
+
+// Register 以 name 注册 l，同名时覆盖
+func (r *Registry) Register(name string, l Logger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cancelRevert(name)
+	r.loggers[name] = l
+}
+
+// Unregister 移除 name 对应的 Logger
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cancelRevert(name)
+	delete(r.loggers, name)
+}
+
+func (r *Registry) Get(name string) (Logger, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l, ok := r.loggers[name]
+	return l, ok
+}
+
+// Levels 返回所有已注册 Logger 的级别，按名称排序
+func (r *Registry) Levels() []LevelInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	infos := make([]LevelInfo, 0, len(r.loggers))
+	for name := range r.loggers {
+		infos = append(infos, r.levelInfo(name))
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Level 返回 name 对应 Logger 的级别信息
+func (r *Registry) Level(name string) (LevelInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.loggers[name]; !ok {
+		return LevelInfo{}, ErrUnknownLogger
+	}
+	return r.levelInfo(name), nil
+}
+
+// SetLevel 修改 name 对应 Logger 的级别，revertAfter 大于 0 时到期自动恢复为修改前的级别；
+// 恢复前再次修改时仍恢复为最初的级别，revertAfter 为 0 则取消待执行的恢复
+func (r *Registry) SetLevel(name string, level Level, revertAfter time.Duration) (LevelInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l, ok := r.loggers[name]
+	if !ok {
+		return LevelInfo{}, ErrUnknownLogger
+	}
+
+	original := l.GetLevel()
+	if rv, ok := r.reverts[name]; ok {
+		original = rv.level
+	}
+	r.cancelRevert(name)
+
+	l.SetLevel(level)
+	if revertAfter > 0 {
+		rv := &revert{at: time.Now().Add(revertAfter), level: original}
+		rv.timer = time.AfterFunc(revertAfter, func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			if r.reverts[name] != rv {
+				return
+			}
+			delete(r.reverts, name)
+			l.SetLevel(rv.level)
+		})
+		r.reverts[name] = rv
+	}
+	return r.levelInfo(name), nil
+}
+
+func (r *Registry) levelInfo(name string) LevelInfo {
+	info := LevelInfo{Name: name, Level: r.loggers[name].GetLevel()}
+	if rv, ok := r.reverts[name]; ok {
+		at, level := rv.at, rv.level
+		info.RevertAt = &at
+		info.RevertLevel = &level
+	}
+	return info
+}
+
+func (r *Registry) cancelRevert(name string) {
+	if rv, ok := r.reverts[name]; ok {
+		rv.timer.Stop()
+		delete(r.reverts, name)
+	}
+}
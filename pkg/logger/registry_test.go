@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+type levelLogger struct {
+	Logger
+	level AtomicLevel
+}
+
+func (l levelLogger) SetLevel(level Level) { l.level.SetLevel(level) }
+func (l levelLogger) GetLevel() Level      { return l.level.Level() }
+
+func TestRegistrySetLevel(t *testing.T) {
+	r := NewRegistry()
+	l := levelLogger{NewNop(), NewAtomicLevel(InfoLevel)}
+	r.Register("http", l)
+
+	if _, err := r.SetLevel("grpc", DebugLevel, 0); err != ErrUnknownLogger {
+		t.Fatalf("expected ErrUnknownLogger, got %v", err)
+	}
+
+	info, err := r.SetLevel("http", DebugLevel, 50*time.Millisecond)
+	if err != nil || info.Level != DebugLevel || info.RevertAt == nil || *info.RevertLevel != InfoLevel {
+		t.Fatalf("unexpected level info %+v %v", info, err)
+	}
+	// 恢复前再次修改，仍恢复为最初的级别
+	if _, err := r.SetLevel("http", WarnLevel, 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if l.GetLevel() != InfoLevel {
+		t.Fatalf("level not reverted: %v", l.GetLevel())
+	}
+	if infos := r.Levels(); len(infos) != 1 || infos[0].RevertAt != nil {
+		t.Fatalf("unexpected levels %+v", infos)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, want := range map[string]Level{"debug": DebugLevel, "WARN": WarnLevel, "error": ErrorLevel} {
+		if l, err := ParseLevel(s); err != nil || l != want {
+			t.Fatalf("ParseLevel(%q) = %v, %v", s, l, err)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// 日志被丢弃的原因
+const (
+	DropSampled     = "sampled"
+	DropRateLimited = "rate_limited"
+	DropQueueFull   = "queue_full"
+)
+
+var dropped sync.Map // reason -> *uint64
+
+// RecordDropped 记录一条因 reason 被丢弃的日志，供 Logger 实现调用
+func RecordDropped(reason string) {
+	v, ok := dropped.Load(reason)
+	if !ok {
+		v, _ = dropped.LoadOrStore(reason, new(uint64))
+	}
+	atomic.AddUint64(v.(*uint64), 1)
+}
+
+// DroppedCount 返回因 reason 被丢弃的日志条数
+func DroppedCount(reason string) uint64 {
+	if v, ok := dropped.Load(reason); ok {
+		return atomic.LoadUint64(v.(*uint64))
+	}
+	return 0
+}
+
+// Dropped 返回按原因统计的被丢弃日志条数
+func Dropped() map[string]uint64 {
+	counts := make(map[string]uint64)
+	dropped.Range(func(k, v interface{}) bool {
+		counts[k.(string)] = atomic.LoadUint64(v.(*uint64))
+		return true
+	})
+	return counts
+}
@@ -0,0 +1,46 @@
+// Package stdlog 将 pkg/logger 与标准库 log、log/slog 互相桥接，
+// 使通过标准库输出日志的第三方库同样使用统一的格式与级别
+package stdlog
+
+import (
+	"bytes"
+	"io"
+	"log"
+
+	"github.com/mel2oo/juice/pkg/logger"
+)
+
+// NewWriter 返回将每次 Write 作为一条 level 级别日志写入 l 的 io.Writer，末尾的换行会被去掉
+func NewWriter(l logger.Logger, level logger.Level) io.Writer {
+	return &writer{log: l, level: level}
+}
+
+type writer struct {
+	log   logger.Logger
+	level logger.Level
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.log.Log(w.level, string(bytes.TrimSuffix(p, []byte("\n"))))
+	return len(p), nil
+}
+
+// NewStdLog 返回输出到 l 的 *log.Logger，供只接受 *log.Logger 的第三方库使用，如 http.Server.ErrorLog
+func NewStdLog(l logger.Logger, level logger.Level) *log.Logger {
+	return log.New(NewWriter(l, level), "", 0)
+}
+
+// RedirectStdLog 将标准库 log 包的全局输出重定向到 l，返回恢复原设置的函数
+func RedirectStdLog(l logger.Logger, level logger.Level) func() {
+	flags, prefix, output := log.Flags(), log.Prefix(), log.Writer()
+
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(NewWriter(l, level))
+
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(output)
+	}
+}
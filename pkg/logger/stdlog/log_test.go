@@ -0,0 +1,30 @@
+package stdlog
+
+import (
+	"log"
+	"testing"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/pkg/logger/observer"
+)
+
+func TestStdLog(t *testing.T) {
+	l, logs := observer.New(logger.DebugLevel)
+
+	NewStdLog(l, logger.WarnLevel).Printf("http: TLS handshake error from %s", "1.2.3.4")
+
+	restore := RedirectStdLog(l, logger.InfoLevel)
+	log.Print("from global")
+	restore()
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].Level != logger.WarnLevel || entries[0].Message != "http: TLS handshake error from 1.2.3.4" {
+		t.Fatalf("unexpected entry %+v", entries[0])
+	}
+	if entries[1].Level != logger.InfoLevel || entries[1].Message != "from global" {
+		t.Fatalf("unexpected entry %+v", entries[1])
+	}
+}
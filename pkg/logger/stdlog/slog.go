@@ -0,0 +1,260 @@
+//go:build go1.21
+// +build go1.21
+
+package stdlog
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/logger"
+)
+
+// ToSlogLevel 将 logger.Level 转换为 slog.Level，DPanic、Panic、Fatal 依次高于 slog.LevelError
+func ToSlogLevel(l logger.Level) slog.Level {
+	switch {
+	case l <= logger.DebugLevel:
+		return slog.LevelDebug
+	case l == logger.InfoLevel:
+		return slog.LevelInfo
+	case l == logger.WarnLevel:
+		return slog.LevelWarn
+	case l == logger.ErrorLevel:
+		return slog.LevelError
+	default:
+		return slog.LevelError + slog.Level(l-logger.ErrorLevel)
+	}
+}
+
+// FromSlogLevel 将 slog.Level 转换为 logger.Level，介于两个标准级别之间的取较低者
+func FromSlogLevel(l slog.Level) logger.Level {
+	switch {
+	case l < slog.LevelInfo:
+		return logger.DebugLevel
+	case l < slog.LevelWarn:
+		return logger.InfoLevel
+	case l < slog.LevelError:
+		return logger.WarnLevel
+	case l < slog.LevelError+1:
+		return logger.ErrorLevel
+	case l < slog.LevelError+2:
+		return logger.DPanicLevel
+	case l < slog.LevelError+3:
+		return logger.PanicLevel
+	default:
+		return logger.FatalLevel
+	}
+}
+
+// NewSlogHandler 返回输出到 l 的 slog.Handler，级别由 l.GetLevel 决定，分组以 "group.key" 形式展开
+func NewSlogHandler(l logger.Logger) slog.Handler {
+	return &slogHandler{log: l}
+}
+
+// SetSlogDefault 将 slog 的默认 Logger 设为输出到 l(log 包的全局输出随之转发到 l)，返回恢复原设置的函数
+func SetSlogDefault(l logger.Logger) func() {
+	def := slog.Default()
+	flags, prefix, output := log.Flags(), log.Prefix(), log.Writer()
+
+	slog.SetDefault(slog.New(NewSlogHandler(l)))
+
+	return func() {
+		slog.SetDefault(def)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(output)
+	}
+}
+
+type slogHandler struct {
+	log    logger.Logger
+	prefix string
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return FromSlogLevel(level) >= h.log.GetLevel()
+}
+
+// Handle 以记录自身的级别输出，调用位置取自 r.PC，记录在 source 字段中
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := make([]logger.Field, 0, r.NumAttrs()+1)
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		fields = append(fields, logger.String(slog.SourceKey, frame.File+":"+strconv.Itoa(frame.Line)))
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		fields = append(fields, attrFields(h.prefix, a)...)
+		return true
+	})
+
+	h.log.With(fields...).Log(FromSlogLevel(r.Level), r.Message)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []logger.Field
+	for _, a := range attrs {
+		fields = append(fields, attrFields(h.prefix, a)...)
+	}
+	return &slogHandler{log: h.log.With(fields...), prefix: h.prefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{log: h.log, prefix: h.prefix + name + "."}
+}
+
+// attrFields 将 slog.Attr 转换为 logger.Field，分组递归展开
+func attrFields(prefix string, a slog.Attr) []logger.Field {
+	v := a.Value.Resolve()
+	if v.Kind() != slog.KindGroup {
+		if a.Key == "" {
+			return nil
+		}
+		return []logger.Field{logger.Any(prefix+a.Key, v.Any())}
+	}
+
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	var fields []logger.Field
+	for _, ga := range v.Group() {
+		fields = append(fields, attrFields(prefix, ga)...)
+	}
+	return fields
+}
+
+// FromSlogHandler 将 slog.Handler 包装为 logger.Logger；SetLevel 在 handler 自身的级别之外再做一层过滤，
+// 默认不过滤；Panic、Fatal 只记录，不会 panic 或退出
+func FromSlogHandler(h slog.Handler) logger.Logger {
+	return &slogLogger{
+		handler: h,
+		level:   logger.NewAtomicLevel(logger.DebugLevel),
+	}
+}
+
+type slogLogger struct {
+	handler slog.Handler
+	level   logger.AtomicLevel
+}
+
+// log 调用层级固定为 用户代码 -> 导出方法 -> log，source 指向用户代码
+func (s *slogLogger) log(level logger.Level, msg string, fields []logger.Field) {
+	ctx := context.Background()
+	sl := ToSlogLevel(level)
+	if !s.level.Enabled(level) || !s.handler.Enabled(ctx, sl) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), sl, msg, pcs[0])
+	for _, f := range fields {
+		r.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	s.handler.Handle(ctx, r)
+}
+
+func (s *slogLogger) String() string {
+	return "slog"
+}
+
+// Type 返回 slog.Handler
+func (s *slogLogger) Type() interface{} {
+	return s.handler
+}
+
+func (s *slogLogger) With(fields ...logger.Field) logger.Logger {
+	attrs := make([]slog.Attr, len(fields))
+	for i, f := range fields {
+		attrs[i] = slog.Any(f.Key, f.Value)
+	}
+	return &slogLogger{handler: s.handler.WithAttrs(attrs), level: s.level}
+}
+
+func (s *slogLogger) SetLevel(level logger.Level) {
+	s.level.SetLevel(level)
+}
+
+func (s *slogLogger) GetLevel() logger.Level {
+	return s.level.Level()
+}
+
+func (s *slogLogger) Sync() error {
+	return nil
+}
+
+func (s *slogLogger) Close() error {
+	return nil
+}
+
+func (s *slogLogger) Log(level logger.Level, msg string) {
+	s.log(level, msg, nil)
+}
+
+func (s *slogLogger) Debug(v ...interface{}) {
+	s.log(logger.DebugLevel, fmt.Sprint(v...), nil)
+}
+
+func (s *slogLogger) Debugf(format string, args ...interface{}) {
+	s.log(logger.DebugLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (s *slogLogger) Info(v ...interface{}) {
+	s.log(logger.InfoLevel, fmt.Sprint(v...), nil)
+}
+
+func (s *slogLogger) Infof(format string, args ...interface{}) {
+	s.log(logger.InfoLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (s *slogLogger) Warn(v ...interface{}) {
+	s.log(logger.WarnLevel, fmt.Sprint(v...), nil)
+}
+
+func (s *slogLogger) Warnf(format string, args ...interface{}) {
+	s.log(logger.WarnLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (s *slogLogger) Error(v ...interface{}) {
+	s.log(logger.ErrorLevel, fmt.Sprint(v...), nil)
+}
+
+func (s *slogLogger) Errorf(format string, args ...interface{}) {
+	s.log(logger.ErrorLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (s *slogLogger) DPanic(v ...interface{}) {
+	s.log(logger.DPanicLevel, fmt.Sprint(v...), nil)
+}
+
+func (s *slogLogger) Panic(v ...interface{}) {
+	s.log(logger.PanicLevel, fmt.Sprint(v...), nil)
+}
+
+func (s *slogLogger) Fatal(v ...interface{}) {
+	s.log(logger.FatalLevel, fmt.Sprint(v...), nil)
+}
+
+func (s *slogLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	s.log(logger.DebugLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
+
+func (s *slogLogger) Infow(msg string, keysAndValues ...interface{}) {
+	s.log(logger.InfoLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
+
+func (s *slogLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	s.log(logger.WarnLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
+
+func (s *slogLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	s.log(logger.ErrorLevel, msg, logger.KeysAndValues(keysAndValues...))
+}
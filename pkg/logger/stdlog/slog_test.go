@@ -0,0 +1,58 @@
+//go:build go1.21
+// +build go1.21
+
+package stdlog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/pkg/logger/observer"
+)
+
+func TestSlogHandler(t *testing.T) {
+	l, logs := observer.New(logger.InfoLevel)
+	sl := slog.New(NewSlogHandler(l))
+
+	sl.Debug("dropped")
+	sl.With("trace_id", "t1").WithGroup("req").Warn("slow", "path", "/a", slog.Group("db", "ms", 12))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", entries)
+	}
+	e := entries[0]
+	m := e.ContextMap()
+	if e.Level != logger.WarnLevel || e.Message != "slow" || m["trace_id"] != "t1" || m["req.path"] != "/a" || m["req.db.ms"] != int64(12) {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+	if src, _ := m[slog.SourceKey].(string); !strings.Contains(src, "slog_test.go") {
+		t.Fatalf("unexpected source %v", m[slog.SourceKey])
+	}
+
+	// 高于 Error 的级别按自身级别输出
+	logs.TakeAll()
+	sl.Log(context.Background(), ToSlogLevel(logger.DPanicLevel), "dpanic")
+	if entries := logs.All(); len(entries) != 1 || entries[0].Level != logger.DPanicLevel {
+		t.Fatalf("expected dpanic entry, got %+v", entries)
+	}
+}
+
+func TestFromSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := FromSlogHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{AddSource: true}))
+
+	l.With(logger.String("trace_id", "t1")).Warnw("slow", "path", "/a")
+	l.SetLevel(logger.ErrorLevel)
+	l.Warn("dropped")
+
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 || !strings.Contains(out, "level=WARN") ||
+		!strings.Contains(out, "trace_id=t1 path=/a") || !strings.Contains(out, "slog_test.go") {
+		t.Fatalf("unexpected output %q", out)
+	}
+}
@@ -0,0 +1,165 @@
+package writer
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mel2oo/juice/pkg/logger"
+)
+
+// ErrClosed 向已关闭的 Async 写入
+var ErrClosed = errors.New("writer: closed")
+
+// DefaultQueueSize Async 默认的队列长度
+const DefaultQueueSize = 8192
+
+// DroppedTotal 返回所有 Async 因队列已满丢弃的日志条数之和
+func DroppedTotal() uint64 {
+	return logger.DroppedCount(logger.DropQueueFull)
+}
+
+// Async 带有界队列的异步 Writer：Write 只将数据复制入队，由后台 goroutine 写入底层 Writer，
+// 队列已满时丢弃并计数，不阻塞调用方
+type Async struct {
+	// 64 位原子操作的字段放在最前，保证 32 位平台上的对齐
+	dropped uint64
+	written uint64
+
+	w      io.Writer
+	queue  chan asyncEntry
+	quit   chan struct{}
+	done   chan struct{}
+	mu     sync.RWMutex
+	closed bool
+}
+
+type asyncEntry struct {
+	p     []byte
+	flush chan struct{}
+}
+
+// NewAsync size 小于等于 0 时使用 DefaultQueueSize
+func NewAsync(w io.Writer, size int) *Async {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+
+	a := &Async{
+		w:     w,
+		queue: make(chan asyncEntry, size),
+		quit:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *Async) run() {
+	defer close(a.done)
+
+	for {
+		select {
+		case e := <-a.queue:
+			a.handle(e)
+		case <-a.quit:
+			// 写完关闭前已入队的数据
+			for {
+				select {
+				case e := <-a.queue:
+					a.handle(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (a *Async) handle(e asyncEntry) {
+	if e.flush != nil {
+		if s, ok := a.w.(interface{ Sync() error }); ok {
+			s.Sync()
+		}
+		close(e.flush)
+		return
+	}
+	if _, err := a.w.Write(e.p); err == nil {
+		atomic.AddUint64(&a.written, 1)
+	}
+}
+
+func (a *Async) Write(p []byte) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return 0, ErrClosed
+	}
+
+	// 调用方(如 zap)会复用 p，需要复制
+	b := make([]byte, len(p))
+	copy(b, p)
+
+	select {
+	case a.queue <- asyncEntry{p: b}:
+	default:
+		atomic.AddUint64(&a.dropped, 1)
+		logger.RecordDropped(logger.DropQueueFull)
+	}
+	return len(p), nil
+}
+
+// Sync 等待已入队的数据写入底层 Writer，底层实现 Sync 时一并调用；
+// 与 Close 并发时随 Close 写完队列后返回
+func (a *Async) Sync() error {
+	a.mu.RLock()
+	closed := a.closed
+	a.mu.RUnlock()
+	if closed {
+		return nil
+	}
+
+	flush := make(chan struct{})
+	select {
+	case a.queue <- asyncEntry{flush: flush}:
+	case <-a.quit:
+		<-a.done
+		return nil
+	}
+
+	select {
+	case <-flush:
+	case <-a.done:
+	}
+	return nil
+}
+
+// Close 写完队列中的数据后关闭，底层实现 io.Closer 时一并关闭
+func (a *Async) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.quit)
+	a.mu.Unlock()
+
+	<-a.done
+	if c, ok := a.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Dropped 返回因队列已满丢弃的条数
+func (a *Async) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Written 返回已写入底层 Writer 的条数
+func (a *Async) Written() uint64 {
+	return atomic.LoadUint64(&a.written)
+}
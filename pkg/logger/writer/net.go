@@ -0,0 +1,123 @@
+package writer
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultJournaldSocket journald 原生协议的默认 socket
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// 连接失败后的重连退避区间
+const (
+	minReconnectBackoff = 100 * time.Millisecond
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// ErrReconnecting 连接失败后的退避期内写入，日志被丢弃
+var ErrReconnecting = errors.New("writer: waiting to reconnect")
+
+// NetWriter 将每次 Write 作为一条消息发送到 network/address，连接在首次写入时建立，写入失败时重连一次；
+// 连接失败后按指数退避重连，退避期内的写入直接返回 ErrReconnecting，每次写入带有 timeout 的写超时。
+// tcp、unix 等流式连接按 RFC 6587 octet-counting 分帧(消息前加 "长度 ")，udp、unixgram 每条消息一个数据报
+type NetWriter struct {
+	mu       sync.Mutex
+	network  string
+	address  string
+	framing  bool
+	timeout  time.Duration
+	conn     net.Conn
+	backoff  time.Duration
+	nextDial time.Time
+}
+
+// NewSyslog 返回写入 syslog 服务的 NetWriter，network 为 udp、tcp、unix 或 unixgram
+func NewSyslog(network, address string) *NetWriter {
+	return &NetWriter{
+		network: network,
+		address: address,
+		framing: network != "udp" && network != "udp4" && network != "udp6" && network != "unixgram",
+		timeout: 5 * time.Second,
+	}
+}
+
+// NewJournald 返回以原生协议写入 journald 的 NetWriter，socket 为空时使用 DefaultJournaldSocket；
+// 单条日志受数据报大小限制，超长的日志会写入失败
+func NewJournald(socket string) *NetWriter {
+	if socket == "" {
+		socket = DefaultJournaldSocket
+	}
+	return &NetWriter{
+		network: "unixgram",
+		address: socket,
+		timeout: 5 * time.Second,
+	}
+}
+
+func (w *NetWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	msg := p
+	if w.framing {
+		msg = append([]byte(strconv.Itoa(len(p))+" "), p...)
+	}
+
+	var err error
+	for i := 0; i < 2; i++ {
+		if w.conn == nil {
+			if err = w.dial(); err != nil {
+				return 0, err
+			}
+		}
+		w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+		if _, err = w.conn.Write(msg); err == nil {
+			return len(p), nil
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	return 0, err
+}
+
+// dial 建立连接，失败时延长退避时间
+func (w *NetWriter) dial() error {
+	if time.Now().Before(w.nextDial) {
+		return ErrReconnecting
+	}
+
+	conn, err := net.DialTimeout(w.network, w.address, w.timeout)
+	if err != nil {
+		if w.backoff *= 2; w.backoff < minReconnectBackoff {
+			w.backoff = minReconnectBackoff
+		} else if w.backoff > maxReconnectBackoff {
+			w.backoff = maxReconnectBackoff
+		}
+		w.nextDial = time.Now().Add(w.backoff)
+		return err
+	}
+
+	w.conn = conn
+	w.backoff = 0
+	w.nextDial = time.Time{}
+	return nil
+}
+
+func (w *NetWriter) Sync() error {
+	return nil
+}
+
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,211 @@
+package writer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeWriter 按时间周期滚动的文件 Writer，文件名由 pattern 按周期起始时间生成，
+// pattern 支持 %Y %m %d %H %M 及 %%，如 "logs/app.%Y-%m-%d.log"
+type TimeWriter struct {
+	mu         sync.Mutex
+	pattern    string
+	hourly     bool
+	maxBackups int
+	maxAge     time.Duration
+
+	file    *os.File
+	current string
+	next    time.Time
+	now     func() time.Time
+}
+
+// NewTimeWriter hourly 为 false 时按天滚动；滚动后保留最近 maxBackups 个旧文件，并删除修改时间早于 maxAge 的旧文件，
+// 两者为 0 时不限制
+func NewTimeWriter(pattern string, hourly bool, maxBackups int, maxAge time.Duration) *TimeWriter {
+	return &TimeWriter{
+		pattern:    pattern,
+		hourly:     hourly,
+		maxBackups: maxBackups,
+		maxAge:     maxAge,
+		now:        time.Now,
+	}
+}
+
+// Filename 返回当前正在写入的文件名
+func (w *TimeWriter) Filename() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.current
+}
+
+func (w *TimeWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if now := w.now(); w.file == nil || !now.Before(w.next) {
+		if err := w.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+	return w.file.Write(p)
+}
+
+func (w *TimeWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+func (w *TimeWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *TimeWriter) rotate(now time.Time) error {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := start.AddDate(0, 0, 1)
+	if w.hourly {
+		start = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
+		next = start.Add(time.Hour)
+	}
+
+	name := expandPattern(w.pattern, start)
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	if w.file != nil {
+		w.file.Close()
+	}
+	w.file, w.current, w.next = f, name, next
+
+	w.cleanup(now)
+	return nil
+}
+
+// cleanup 删除超出 maxBackups 或 maxAge 的旧文件
+func (w *TimeWriter) cleanup(now time.Time) {
+	if w.maxBackups <= 0 && w.maxAge <= 0 {
+		return
+	}
+
+	matches, err := filepath.Glob(globPattern(w.pattern))
+	if err != nil {
+		return
+	}
+	re := patternRegexp(w.pattern)
+
+	type backup struct {
+		name    string
+		modTime time.Time
+	}
+	backups := make([]backup, 0, len(matches))
+	for _, name := range matches {
+		if name == w.current || !re.MatchString(name) {
+			continue
+		}
+		if info, err := os.Stat(name); err == nil && !info.IsDir() {
+			backups = append(backups, backup{name, info.ModTime()})
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.After(backups[j].modTime) })
+
+	for i, b := range backups {
+		if (w.maxBackups > 0 && i >= w.maxBackups) || (w.maxAge > 0 && now.Sub(b.modTime) > w.maxAge) {
+			os.Remove(b.name)
+		}
+	}
+}
+
+var patternLayouts = map[byte]string{
+	'Y': "2006",
+	'm': "01",
+	'd': "02",
+	'H': "15",
+	'M': "04",
+}
+
+func expandPattern(pattern string, t time.Time) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '%' && i+1 < len(pattern) {
+			if layout, ok := patternLayouts[pattern[i+1]]; ok {
+				b.WriteString(t.Format(layout))
+				i++
+				continue
+			}
+			if pattern[i+1] == '%' {
+				b.WriteByte('%')
+				i++
+				continue
+			}
+		}
+		b.WriteByte(pattern[i])
+	}
+	return b.String()
+}
+
+// patternRegexp 返回严格匹配 pattern 生成的文件名的正则，避免 glob 误匹配其他 pattern 的文件
+func patternRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '%' && i+1 < len(pattern) {
+			if layout, ok := patternLayouts[pattern[i+1]]; ok {
+				fmt.Fprintf(&b, `\d{%d}`, len(layout))
+				i++
+				continue
+			}
+			if pattern[i+1] == '%' {
+				i++
+			}
+		}
+		b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// globPattern 将 pattern 中的时间占位符替换为 *，用于查找旧文件
+func globPattern(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '%' && i+1 < len(pattern) {
+			if _, ok := patternLayouts[pattern[i+1]]; ok {
+				b.WriteByte('*')
+				i++
+				continue
+			}
+			if pattern[i+1] == '%' {
+				b.WriteByte('%')
+				i++
+				continue
+			}
+		}
+		b.WriteByte(pattern[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+// Package writer 提供日志文件的输出：按大小或按天/小时滚动、旧文件保留策略以及异步缓冲写入，
+// zap 与 default 两种实现共用
+package writer
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/natefinch/lumberjack"
+)
+
+// NewFile 根据 opts 为 filename 创建文件 Writer：
+// 设置了 Rotation 时按时间滚动(文件名取 FilePattern，为空时在 filename 的扩展名前追加日期)，否则按 MaxSize 滚动；
+// 旧文件按 MaxBackups 与 MaxAge(天)清理；AsyncQueueSize 大于 0 时使用异步写入
+func NewFile(opts *logger.Options, filename string) io.WriteCloser {
+	var w io.WriteCloser
+	switch opts.Rotation {
+	case logger.RotationDaily, logger.RotationHourly:
+		hourly := opts.Rotation == logger.RotationHourly
+		w = NewTimeWriter(
+			filePattern(opts, filename, hourly),
+			hourly,
+			opts.MaxBackups,
+			time.Duration(opts.MaxAge)*24*time.Hour,
+		)
+	default:
+		//https://github.com/natefinch/lumberjack
+		w = &lumberjack.Logger{
+			Filename:   filename,
+			MaxSize:    opts.MaxSize, // megabytes
+			MaxBackups: opts.MaxBackups,
+			MaxAge:     opts.MaxAge,   //days
+			Compress:   opts.Compress, // disabled by default
+			LocalTime:  true,
+		}
+	}
+
+	if opts.AsyncQueueSize > 0 {
+		return NewAsync(w, opts.AsyncQueueSize)
+	}
+	return w
+}
+
+// filePattern filename 本身含有 %Y 等占位符时直接作为 pattern；
+// 否则使用 FilePattern(相对路径基于 filename 所在目录)，未设置时由 filename 生成，如 app.log 按天滚动为 app.%Y-%m-%d.log
+func filePattern(opts *logger.Options, filename string, hourly bool) string {
+	if strings.Contains(filename, "%") {
+		return filename
+	}
+	if p := opts.FilePattern; p != "" {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(filepath.Dir(filename), p)
+	}
+
+	layout := ".%Y-%m-%d"
+	if hourly {
+		layout = ".%Y-%m-%d-%H"
+	}
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + layout + ext
+}
@@ -0,0 +1,155 @@
+package writer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimeWriter(t *testing.T) {
+	dir := t.TempDir()
+	// 与 pattern 前缀相同的其他文件不应被清理
+	other := filepath.Join(dir, "app.error.log")
+	ioutil.WriteFile(other, []byte("x"), 0644)
+
+	now := time.Date(2021, 5, 1, 10, 30, 0, 0, time.Local)
+	w := NewTimeWriter(filepath.Join(dir, "app.%Y%m%d%H.log"), true, 2, 0)
+	w.now = func() time.Time { return now }
+	defer w.Close()
+
+	for i := 0; i < 4; i++ {
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatal(err)
+		}
+		// 旧文件的修改时间需要有先后
+		os.Chtimes(w.Filename(), now, now)
+		now = now.Add(time.Hour)
+	}
+
+	if want := filepath.Join(dir, "app.2021050113.log"); w.Filename() != want {
+		t.Fatalf("unexpected filename %s", w.Filename())
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "app.2021*.log"))
+	if len(files) != 3 {
+		t.Fatalf("expected current file and 2 backups, got %v", files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app.2021050110.log")); !os.IsNotExist(err) {
+		t.Fatalf("oldest backup should be removed: %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Fatalf("unrelated file removed: %v", err)
+	}
+}
+
+type slowWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	release chan struct{}
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestAsync(t *testing.T) {
+	w := &slowWriter{release: make(chan struct{})}
+	a := NewAsync(w, 2)
+
+	// 后台 goroutine 阻塞在第一条，队列最多再容纳 2 条
+	for i := 0; i < 10; i++ {
+		if _, err := a.Write([]byte("x")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.Dropped() < 7 {
+		t.Fatalf("expected drops, got %d", a.Dropped())
+	}
+
+	close(w.release)
+	if err := a.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if a.Written()+a.Dropped() != 10 {
+		t.Fatalf("written %d + dropped %d != 10", a.Written(), a.Dropped())
+	}
+
+	a.Close()
+	if _, err := a.Write([]byte("x")); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+// 队列已满时 Sync 阻塞，不应妨碍 Close 获取锁
+func TestAsyncSyncDuringClose(t *testing.T) {
+	w := &slowWriter{release: make(chan struct{})}
+	a := NewAsync(w, 1)
+	a.Write([]byte("x"))
+	a.Write([]byte("x"))
+
+	synced := make(chan struct{})
+	go func() {
+		a.Sync()
+		close(synced)
+	}()
+
+	closed := make(chan struct{})
+	go func() {
+		a.Close()
+		close(closed)
+	}()
+
+	time.Sleep(time.Millisecond * 20)
+	close(w.release)
+
+	for _, ch := range []chan struct{}{synced, closed} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("Sync/Close deadlocked")
+		}
+	}
+}
+
+// 连接失败后在退避期内不再重新连接
+func TestNetWriterBackoff(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	w := NewSyslog("tcp", addr)
+	if _, err := w.Write([]byte("a")); err == nil || err == ErrReconnecting {
+		t.Fatalf("expected dial error, got %v", err)
+	}
+	if _, err := w.Write([]byte("b")); err != ErrReconnecting {
+		t.Fatalf("expected ErrReconnecting, got %v", err)
+	}
+
+	lis, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer lis.Close()
+	go func() {
+		if conn, err := lis.Accept(); err == nil {
+			ioutil.ReadAll(conn)
+		}
+	}()
+
+	time.Sleep(minReconnectBackoff)
+	if _, err := w.Write([]byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+}
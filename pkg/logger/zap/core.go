@@ -0,0 +1,83 @@
+package zap
+
+import (
+	"sort"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ToZap 返回 l 对应的 *zap.Logger：ZapLogger 返回带有 Prefix 名称的底层 Logger，
+// 其他实现通过桥接 Core 转发，便于依赖 *zap.Logger 的组件(如 ctxzap)复用任意 logger.Logger。
+func ToZap(l logger.Logger) *zap.Logger {
+	if z, ok := l.(*ZapLogger); ok {
+		return z.Logger.Named(z.opts.Prefix)
+	}
+	if zl, ok := l.Type().(*zap.Logger); ok {
+		return zl
+	}
+	return zap.New(NewCore(l))
+}
+
+// NewCore 将 zapcore.Entry 转发给 l 的 zapcore.Core
+func NewCore(l logger.Logger) zapcore.Core {
+	return &bridgeCore{log: l}
+}
+
+type bridgeCore struct {
+	log    logger.Logger
+	fields []zapcore.Field
+}
+
+func (c *bridgeCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *bridgeCore) With(fields []zapcore.Field) zapcore.Core {
+	return &bridgeCore{
+		log:    c.log,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *bridgeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c *bridgeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, logger.Any(k, enc.Fields[k]))
+	}
+
+	switch {
+	case ent.Level <= zapcore.DebugLevel:
+		c.log.Debugw(ent.Message, kvs...)
+	case ent.Level == zapcore.InfoLevel:
+		c.log.Infow(ent.Message, kvs...)
+	case ent.Level == zapcore.WarnLevel:
+		c.log.Warnw(ent.Message, kvs...)
+	default:
+		c.log.Errorw(ent.Message, kvs...)
+	}
+	return nil
+}
+
+func (c *bridgeCore) Sync() error {
+	return nil
+}
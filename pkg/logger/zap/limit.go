@@ -0,0 +1,61 @@
+package zap
+
+import (
+	"github.com/mel2oo/juice/pkg/logger"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/time/rate"
+)
+
+// exemptCore 不低于 level 的日志直接交给 raw，其余交给经过采样、限速的 Core
+type exemptCore struct {
+	zapcore.Core
+	raw   zapcore.Core
+	level zapcore.Level
+}
+
+func (c *exemptCore) With(fields []zapcore.Field) zapcore.Core {
+	return &exemptCore{
+		Core:  c.Core.With(fields),
+		raw:   c.raw.With(fields),
+		level: c.level,
+	}
+}
+
+func (c *exemptCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level >= c.level {
+		return c.raw.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// rateLimitCore 对配置了限速的日志消息按令牌桶丢弃，With 派生的 Core 共享同一组限速器
+type rateLimitCore struct {
+	zapcore.Core
+	limiters map[string]*rate.Limiter
+}
+
+func newRateLimitCore(core zapcore.Core, limits map[string]logger.RateLimit) zapcore.Core {
+	limiters := make(map[string]*rate.Limiter, len(limits))
+	for msg, l := range limits {
+		limiters[msg] = rate.NewLimiter(rate.Every(l.Every), l.Burst)
+	}
+	return &rateLimitCore{Core: core, limiters: limiters}
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{
+		Core:     c.Core.With(fields),
+		limiters: c.limiters,
+	}
+}
+
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	if l, ok := c.limiters[ent.Message]; ok && !l.Allow() {
+		logger.RecordDropped(logger.DropRateLimited)
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,95 @@
+package zap
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewLogfmtEncoder 返回 logfmt(key=value)格式的 Encoder，字段顺序与 JSON 编码一致，
+// 嵌套的对象与数组以紧凑 JSON 作为值
+func NewLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{zapcore.NewJSONEncoder(cfg)}
+}
+
+type logfmtEncoder struct {
+	zapcore.Encoder
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	return &logfmtEncoder{e.Encoder.Clone()}
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	kvs, err := encodeOrdered(e.Encoder, ent, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bufferPool.Get()
+	for i, kv := range kvs {
+		if i > 0 {
+			buf.AppendByte(' ')
+		}
+		buf.AppendString(kv.key)
+		buf.AppendByte('=')
+		if kv.value != "" && !strings.ContainsAny(kv.value, " =\"\\\t\r\n") {
+			buf.AppendString(kv.value)
+		} else {
+			buf.AppendString(strconv.Quote(kv.value))
+		}
+	}
+	buf.AppendString(zapcore.DefaultLineEnding)
+	return buf, nil
+}
+
+var bufferPool = buffer.NewPool()
+
+// orderedField 按编码顺序排列的字段，value 为字符串的原文或其他类型的紧凑 JSON
+type orderedField struct {
+	key   string
+	value string
+}
+
+// encodeOrdered 使用 JSON Encoder 编码后按原顺序拆出各字段，
+// 供 logfmt、syslog 等非 JSON 格式复用 zap 对字段、时间、级别的编码
+func encodeOrdered(enc zapcore.Encoder, ent zapcore.Entry, fields []zapcore.Field) ([]orderedField, error) {
+	buf, err := enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Free()
+
+	dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	dec.UseNumber()
+	// 跳过起始的 {
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var kvs []orderedField
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		value := string(raw)
+		if len(raw) > 0 && raw[0] == '"' {
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil {
+				value = s
+			}
+		}
+		kvs = append(kvs, orderedField{key: key.(string), value: value})
+	}
+	return kvs, nil
+}
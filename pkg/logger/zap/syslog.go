@@ -0,0 +1,264 @@
+package zap
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mel2oo/juice/pkg/logger/writer"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// SyslogSDID syslog structured data 的 SD-ID，日志字段作为其 SD-PARAM 输出
+var SyslogSDID = "fields@32473"
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSeverity 将 zap 级别映射为 syslog severity
+func syslogSeverity(l zapcore.Level) int {
+	switch l {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// structuredConfig 时间、级别、消息由 syslog/journald 自身的字段表示，不再作为普通字段输出
+func structuredConfig(cfg zapcore.EncoderConfig) zapcore.EncoderConfig {
+	cfg.TimeKey = ""
+	cfg.LevelKey = ""
+	cfg.MessageKey = ""
+	return cfg
+}
+
+// NewSyslogEncoder 返回 RFC 5424 格式的 Encoder，日志字段映射为 SyslogSDID 下的 structured data
+func NewSyslogEncoder(cfg zapcore.EncoderConfig, app string, facility int) zapcore.Encoder {
+	hostname, _ := os.Hostname()
+	return &syslogEncoder{
+		Encoder:  zapcore.NewJSONEncoder(structuredConfig(cfg)),
+		app:      syslogHeaderValue(app, 48),
+		hostname: syslogHeaderValue(hostname, 255),
+		pid:      strconv.Itoa(os.Getpid()),
+		facility: facility,
+	}
+}
+
+type syslogEncoder struct {
+	zapcore.Encoder
+	app      string
+	hostname string
+	pid      string
+	facility int
+}
+
+func (e *syslogEncoder) Clone() zapcore.Encoder {
+	c := *e
+	c.Encoder = e.Encoder.Clone()
+	return &c
+}
+
+func (e *syslogEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	kvs, err := encodeOrdered(e.Encoder, ent, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bufferPool.Get()
+	fmt.Fprintf(buf, "<%d>1 %s %s %s %s - ",
+		e.facility*8+syslogSeverity(ent.Level),
+		ent.Time.Format("2006-01-02T15:04:05.000000Z07:00"),
+		e.hostname,
+		e.app,
+		e.pid,
+	)
+
+	if len(kvs) == 0 {
+		buf.AppendByte('-')
+	} else {
+		buf.AppendString("[" + SyslogSDID)
+		for _, kv := range kvs {
+			buf.AppendByte(' ')
+			buf.AppendString(syslogParamName(kv.key))
+			buf.AppendString(`="`)
+			buf.AppendString(syslogParamValue.Replace(kv.value))
+			buf.AppendByte('"')
+		}
+		buf.AppendByte(']')
+	}
+
+	if ent.Message != "" {
+		buf.AppendByte(' ')
+		buf.AppendString(ent.Message)
+	}
+	return buf, nil
+}
+
+var syslogParamValue = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogParamName SD-NAME 最长 32 个可打印 ASCII 字符，不能包含 = 空格 ] "
+func syslogParamName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		if c <= ' ' || c >= 127 || c == '=' || c == ']' || c == '"' {
+			name[i] = '_'
+		}
+	}
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return string(name)
+}
+
+func syslogHeaderValue(v string, max int) string {
+	if v == "" {
+		return "-"
+	}
+	v = strings.Map(func(r rune) rune {
+		if r <= ' ' || r >= 127 {
+			return '_'
+		}
+		return r
+	}, v)
+	if len(v) > max {
+		v = v[:max]
+	}
+	return v
+}
+
+// NewJournaldEncoder 返回 journald 原生协议格式的 Encoder，日志字段转换为大写的 journal 字段
+func NewJournaldEncoder(cfg zapcore.EncoderConfig, identifier string) zapcore.Encoder {
+	return &journaldEncoder{
+		Encoder:    zapcore.NewJSONEncoder(structuredConfig(cfg)),
+		identifier: identifier,
+	}
+}
+
+type journaldEncoder struct {
+	zapcore.Encoder
+	identifier string
+}
+
+func (e *journaldEncoder) Clone() zapcore.Encoder {
+	return &journaldEncoder{e.Encoder.Clone(), e.identifier}
+}
+
+func (e *journaldEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	kvs, err := encodeOrdered(e.Encoder, ent, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bufferPool.Get()
+	appendJournalField(buf, "MESSAGE", ent.Message)
+	appendJournalField(buf, "PRIORITY", strconv.Itoa(syslogSeverity(ent.Level)))
+	if e.identifier != "" {
+		appendJournalField(buf, "SYSLOG_IDENTIFIER", e.identifier)
+	}
+	for _, kv := range kvs {
+		appendJournalField(buf, journalFieldName(kv.key), kv.value)
+	}
+	return buf, nil
+}
+
+// appendJournalField 含有换行的值使用二进制格式：名称、换行、8 字节小端长度、值
+func appendJournalField(buf *buffer.Buffer, name, value string) {
+	buf.AppendString(name)
+	if !strings.Contains(value, "\n") {
+		buf.AppendByte('=')
+		buf.AppendString(value)
+		buf.AppendByte('\n')
+		return
+	}
+
+	buf.AppendByte('\n')
+	n := uint64(len(value))
+	for i := 0; i < 8; i++ {
+		buf.AppendByte(byte(n >> (8 * i)))
+	}
+	buf.AppendString(value)
+	buf.AppendByte('\n')
+}
+
+// journalFieldName journal 字段名只能由大写字母、数字和下划线组成，且不能以下划线或数字开头
+func journalFieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || name[0] == '_' || (name[0] >= '0' && name[0] <= '9') {
+		name = append([]byte("F_"), name...)
+	}
+	return string(name)
+}
+
+// isStructuredOutput 判断 Sink.Output 是否为 syslog 或 journald 地址
+func isStructuredOutput(output string) bool {
+	return strings.HasPrefix(output, "syslog+") || strings.HasPrefix(output, "journald")
+}
+
+// newStructuredOutput 解析 Sink.Output 中的 syslog、journald 地址，返回对应的 Writer 与 Encoder：
+//
+//	syslog+udp://127.0.0.1:514?app=api&facility=local0
+//	syslog+tcp://127.0.0.1:601
+//	syslog+unix:///dev/log、syslog+unixgram:///dev/log
+//	journald 或 journald:///run/systemd/journal/socket
+//
+// app 默认为程序名，facility 默认为 user
+func newStructuredOutput(output string, cfg zapcore.EncoderConfig) (io.WriteCloser, zapcore.Encoder, error) {
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	app := u.Query().Get("app")
+	if app == "" {
+		app = filepath.Base(os.Args[0])
+	}
+
+	if output == "journald" || u.Scheme == "journald" {
+		return writer.NewJournald(u.Path), NewJournaldEncoder(cfg, app), nil
+	}
+
+	network := strings.TrimPrefix(u.Scheme, "syslog+")
+	address := u.Host
+	switch network {
+	case "udp", "tcp":
+	case "unix", "unixgram":
+		address = u.Path
+	default:
+		return nil, nil, fmt.Errorf("zap: unsupported syslog network %q", network)
+	}
+
+	facility := syslogFacilities["user"]
+	if f := u.Query().Get("facility"); f != "" {
+		v, ok := syslogFacilities[f]
+		if !ok {
+			return nil, nil, fmt.Errorf("zap: unknown syslog facility %q", f)
+		}
+		facility = v
+	}
+	return writer.NewSyslog(network, address), NewSyslogEncoder(cfg, app, facility), nil
+}
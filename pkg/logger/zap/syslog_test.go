@@ -0,0 +1,127 @@
+package zap
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/logger"
+)
+
+func newStructuredLogger(t *testing.T, output string) logger.Logger {
+	log := NewZapLogger(
+		logger.WithDisableCaller(),
+		logger.WithDisableStacktrace(),
+		logger.WithSink(logger.Sink{Output: output, Level: logger.LevelPtr(logger.DebugLevel)}),
+	)
+	t.Cleanup(func() { log.Close() })
+	return log
+}
+
+var syslogLine = regexp.MustCompile(`^<(\d+)>1 \S+ \S+ api \d+ - \[fields@32473 (.*)\] (.*)$`)
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	log := newStructuredLogger(t, "syslog+udp://"+conn.LocalAddr().String()+"?app=api&facility=local0")
+	log.Warnw("disk full", "path", "/data", "quote", `a"b]`)
+
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := syslogLine.FindStringSubmatch(string(buf[:n]))
+	if m == nil {
+		t.Fatalf("unexpected syslog message %q", buf[:n])
+	}
+	// local0(16)*8 + warning(4)
+	if m[1] != "132" || m[3] != "disk full" {
+		t.Fatalf("unexpected pri/msg %q", buf[:n])
+	}
+	if !strings.Contains(m[2], `path="/data"`) || !strings.Contains(m[2], `quote="a\"b\]"`) {
+		t.Fatalf("unexpected structured data %q", m[2])
+	}
+}
+
+func TestSyslogTCP(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	log := newStructuredLogger(t, "syslog+tcp://"+lis.Addr().String()+"?app=api")
+	go log.Infow("first", "n", 1)
+
+	conn, err := lis.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+	// octet-counting：长度 空格 消息
+	r := bufio.NewReader(conn)
+	size, err := r.ReadString(' ')
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frame := make([]byte, n)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		t.Fatal(err)
+	}
+
+	m := syslogLine.FindStringSubmatch(string(frame))
+	if m == nil || m[1] != "14" || !strings.Contains(m[2], `n="1"`) || m[3] != "first" {
+		t.Fatalf("unexpected syslog frame %q", frame)
+	}
+}
+
+func TestJournald(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not supported: %v", err)
+	}
+	defer conn.Close()
+
+	log := newStructuredLogger(t, "journald://"+socket+"?app=api")
+	log.Errorw("failed", "trace_id", "t1", "stack", "a\nb")
+
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(buf[:n])
+	for _, want := range []string{
+		"MESSAGE=failed\n",
+		"PRIORITY=3\n",
+		"SYSLOG_IDENTIFIER=api\n",
+		"TRACE_ID=t1\n",
+		"STACK\n\x03\x00\x00\x00\x00\x00\x00\x00a\nb\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in %q", want, got)
+		}
+	}
+}
@@ -1,14 +1,18 @@
 package zap
 
 import (
+	"fmt"
 	"io"
+	"math"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/mel2oo/juice/pkg/logger"
-	"github.com/natefinch/lumberjack"
+	"github.com/mel2oo/juice/pkg/logger/writer"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -22,23 +26,42 @@ var DefaultLogger = NewZapLogger(
 
 type ZapLogger struct {
 	*zap.Logger
-	opts *logger.Options
+	opts    *logger.Options
+	level   zap.AtomicLevel
+	closers []io.Closer
 }
 
+// NewZapLogger 创建 ZapLogger，配置无效(如未知的 Encoding、无法解析的 Sink 地址)时 panic，
+// 需要处理错误时使用 New
 func NewZapLogger(opts ...logger.Option) logger.Logger {
+	log, err := New(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return log
+}
+
+// New 创建 ZapLogger，配置无效时返回错误
+func New(opts ...logger.Option) (logger.Logger, error) {
 	options := logger.NewOptions()
 
 	for _, o := range opts {
 		o(options)
 	}
 
-	config := Config{}
-
+	var (
+		config Config
+		err    error
+	)
 	if options.Development {
-		config = newDevelopment(options)
+		config, err = newDevelopment(options)
 	} else {
-		config = newProduction(options)
+		config, err = newProduction(options)
+	}
+	if err != nil {
+		return nil, err
 	}
+	applyLimits(&config, options)
 
 	zopts := make([]zap.Option, 0, 1)
 
@@ -47,57 +70,202 @@ func NewZapLogger(opts ...logger.Option) logger.Logger {
 	return &ZapLogger{
 		logger,
 		options,
-	}
+		config.Level,
+		config.Closers,
+	}, nil
 }
 
-func newDevelopment(opts *logger.Options) Config {
+func newDevelopment(opts *logger.Options) (Config, error) {
 	encoderConfig := zap.NewDevelopmentEncoderConfig()
-	encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	applyEncoderKeys(&encoderConfig, opts)
 
 	var w io.Writer
 	w = os.Stdout
 	sink := zapcore.AddSync(w)
 
+	encoder, err := newEncoder(opts.Encoding, encoderConfig, true)
+	if err != nil {
+		return Config{}, err
+	}
+	outputs, closers, err := newOutputs(opts, encoderConfig)
+	if err != nil {
+		return Config{}, err
+	}
+
 	return Config{
-		Level:             zap.NewAtomicLevelAt(toZapLevel(opts.Level)),
+		Level:             zap.NewAtomicLevelAt(ToZapLevel(opts.Level)),
 		Development:       opts.Development,
 		DisableCaller:     opts.DisableCaller,
 		DisableStacktrace: opts.DisableStacktrace,
-		Encoder:           zapcore.NewConsoleEncoder(encoderConfig),
+		Encoder:           encoder,
 		WriteSyncer:       sink,
-	}
+		Outputs:           outputs,
+		Closers:           closers,
+	}, nil
 }
 
-func newProduction(opts *logger.Options) Config {
+func newProduction(opts *logger.Options) (Config, error) {
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	applyEncoderKeys(&encoderConfig, opts)
+
+	encoder, err := newEncoder(opts.Encoding, encoderConfig, false)
+	if err != nil {
+		return Config{}, err
+	}
+	outputs, closers, err := newOutputs(opts, encoderConfig)
+	if err != nil {
+		return Config{}, err
+	}
 
-	//https://github.com/natefinch/lumberjack
 	var w io.Writer
 	w = os.Stdout
-	if opts.OutputPath != "" {
+	if opts.OutputPath != "" && len(opts.Sinks) == 0 {
 		if !strings.HasSuffix(opts.OutputPath, "/") {
 			opts.OutputPath += "/"
 		}
-		w = &lumberjack.Logger{
-			Filename:   opts.OutputPath + opts.OutputName,
-			MaxSize:    opts.MaxSize, // megabytes
-			MaxBackups: opts.MaxBackups,
-			MaxAge:     opts.MaxAge,   //days
-			Compress:   opts.Compress, // disabled by default
-			LocalTime:  true,
-		}
+		f := writer.NewFile(opts, opts.OutputPath+opts.OutputName)
+		w = f
+		closers = append(closers, f)
 	}
 	sink := zapcore.AddSync(w)
 
 	return Config{
-		Level:             zap.NewAtomicLevelAt(toZapLevel(opts.Level)),
+		Level:             zap.NewAtomicLevelAt(ToZapLevel(opts.Level)),
 		Development:       opts.Development,
 		DisableCaller:     opts.DisableCaller,
 		DisableStacktrace: opts.DisableStacktrace,
-		Encoder:           zapcore.NewConsoleEncoder(encoderConfig), //NewJSONEncoder
+		Encoder:           encoder,
 		WriteSyncer:       sink,
+		Outputs:           outputs,
+		Closers:           closers,
+	}, nil
+}
+
+// applyLimits 将 opts 中的采样、限速配置写入 cfg，被丢弃的日志计入 logger.Dropped
+func applyLimits(cfg *Config, opts *logger.Options) {
+	if s := opts.Sampling; s != nil {
+		cfg.Sampling = &zap.SamplingConfig{
+			Initial:    s.Initial,
+			Thereafter: s.Thereafter,
+			Hook: func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+				if dec&zapcore.LogDropped > 0 {
+					logger.RecordDropped(logger.DropSampled)
+				}
+			},
+		}
+		cfg.SamplingTick = s.Tick
 	}
+	cfg.RateLimits = opts.RateLimits
+	cfg.AlwaysLogLevel = ToZapLevel(opts.AlwaysLogLevel)
+}
+
+// applyEncoderKeys 使用 opts 中非空的 key 覆盖 encoderConfig 的默认 key
+func applyEncoderKeys(cfg *zapcore.EncoderConfig, opts *logger.Options) {
+	if opts.TimeKey != "" {
+		cfg.TimeKey = opts.TimeKey
+	}
+	if opts.LevelKey != "" {
+		cfg.LevelKey = opts.LevelKey
+	}
+	if opts.CallerKey != "" {
+		cfg.CallerKey = opts.CallerKey
+	}
+	if opts.MessageKey != "" {
+		cfg.MessageKey = opts.MessageKey
+	}
+}
+
+// newEncoder 根据 encoding 创建 Encoder，color 为 true 时 console 编码输出彩色级别
+func newEncoder(encoding string, cfg zapcore.EncoderConfig, color bool) (zapcore.Encoder, error) {
+	switch encoding {
+	case logger.EncodingJSON:
+		return zapcore.NewJSONEncoder(cfg), nil
+	case logger.EncodingLogfmt:
+		return NewLogfmtEncoder(cfg), nil
+	case logger.EncodingConsole, "":
+		if color {
+			cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		return zapcore.NewConsoleEncoder(cfg), nil
+	default:
+		return nil, fmt.Errorf("zap: unknown encoding %q", encoding)
+	}
+}
+
+// newOutputs 将 opts.Sinks 转换为 Output，开发模式下输出到终端的 console 编码使用彩色级别；
+// 同时返回需要在 Close 时关闭的文件，出错时关闭已经打开的文件
+func newOutputs(opts *logger.Options, cfg zapcore.EncoderConfig) (outputs []Output, closers []io.Closer, err error) {
+	// FilePattern 只作用于默认输出，Sink 的文件名自带占位符
+	fileOpts := *opts
+	fileOpts.FilePattern = ""
+
+	outputs = make([]Output, 0, len(opts.Sinks))
+	closers = make([]io.Closer, 0, len(opts.Sinks))
+	defer func() {
+		if err != nil {
+			for _, c := range closers {
+				c.Close()
+			}
+		}
+	}()
+
+	for _, s := range opts.Sinks {
+		encoding := s.Encoding
+		if encoding == "" {
+			encoding = opts.Encoding
+		}
+
+		var (
+			w        io.Writer
+			enc      zapcore.Encoder
+			terminal bool
+		)
+		switch {
+		case s.Writer != nil:
+			w = s.Writer
+		case isStructuredOutput(s.Output):
+			nw, e, err := newStructuredOutput(s.Output, cfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			var wc io.WriteCloser = nw
+			if opts.AsyncQueueSize > 0 {
+				wc = writer.NewAsync(nw, opts.AsyncQueueSize)
+			}
+			w, enc = wc, e
+			closers = append(closers, wc)
+		case s.Output == logger.OutputStdout || s.Output == "":
+			w, terminal = os.Stdout, true
+		case s.Output == logger.OutputStderr:
+			w, terminal = os.Stderr, true
+		default:
+			filename := s.Output
+			if !filepath.IsAbs(filename) {
+				filename = filepath.Join(opts.OutputPath, filename)
+			}
+			f := writer.NewFile(&fileOpts, filename)
+			w = f
+			closers = append(closers, f)
+		}
+
+		if enc == nil {
+			if enc, err = newEncoder(encoding, cfg, opts.Development && terminal); err != nil {
+				return nil, nil, err
+			}
+		}
+		output := Output{
+			Encoder:     enc,
+			WriteSyncer: zapcore.AddSync(w),
+		}
+		if s.Level != nil {
+			level := ToZapLevel(*s.Level)
+			output.Level = &level
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs, closers, nil
 }
 
 func (z *ZapLogger) ReplaceGlobals() {
@@ -131,7 +299,30 @@ func (z *ZapLogger) Log(level logger.Level, msg string) {
 	}
 }
 
-func toZapLevel(l logger.Level) zapcore.Level {
+// Sync 将缓冲的日志写入输出
+func (z *ZapLogger) Sync() error {
+	return z.Logger.Sync()
+}
+
+// Close 写完异步队列中的日志并关闭日志文件，标准输出不会被关闭
+func (z *ZapLogger) Close() error {
+	var err error
+	for _, c := range z.closers {
+		multierr.AppendInto(&err, c.Close())
+	}
+	return err
+}
+
+func (z *ZapLogger) SetLevel(level logger.Level) {
+	z.level.SetLevel(ToZapLevel(level))
+}
+
+func (z *ZapLogger) GetLevel() logger.Level {
+	return FromZapLevel(z.level.Level())
+}
+
+// ToZapLevel 将 logger.Level 转换为 zapcore.Level
+func ToZapLevel(l logger.Level) zapcore.Level {
 	var zl zapcore.Level
 	switch l {
 	case logger.DebugLevel:
@@ -152,8 +343,28 @@ func toZapLevel(l logger.Level) zapcore.Level {
 	return zl
 }
 
+// FromZapLevel 将 zapcore.Level 转换为 logger.Level
+func FromZapLevel(l zapcore.Level) logger.Level {
+	switch l {
+	case zapcore.DebugLevel:
+		return logger.DebugLevel
+	case zapcore.InfoLevel:
+		return logger.InfoLevel
+	case zapcore.WarnLevel:
+		return logger.WarnLevel
+	case zapcore.ErrorLevel:
+		return logger.ErrorLevel
+	case zapcore.DPanicLevel:
+		return logger.DPanicLevel
+	case zapcore.PanicLevel:
+		return logger.PanicLevel
+	default:
+		return logger.FatalLevel
+	}
+}
+
 func (z *ZapLogger) Debug(v ...interface{}) {
-	z.Logger.Sugar().Named(z.opts.Prefix).Debug(v)
+	z.Logger.Sugar().Named(z.opts.Prefix).Debug(v...)
 }
 
 func (z *ZapLogger) Debugf(format string, args ...interface{}) {
@@ -161,7 +372,7 @@ func (z *ZapLogger) Debugf(format string, args ...interface{}) {
 }
 
 func (z *ZapLogger) Info(v ...interface{}) {
-	z.Logger.Sugar().Named(z.opts.Prefix).Info(v)
+	z.Logger.Sugar().Named(z.opts.Prefix).Info(v...)
 }
 
 func (z *ZapLogger) Infof(format string, args ...interface{}) {
@@ -169,7 +380,7 @@ func (z *ZapLogger) Infof(format string, args ...interface{}) {
 }
 
 func (z *ZapLogger) Warn(v ...interface{}) {
-	z.Logger.Sugar().Named(z.opts.Prefix).Warn(v)
+	z.Logger.Sugar().Named(z.opts.Prefix).Warn(v...)
 }
 
 func (z *ZapLogger) Warnf(format string, args ...interface{}) {
@@ -177,7 +388,7 @@ func (z *ZapLogger) Warnf(format string, args ...interface{}) {
 }
 
 func (z *ZapLogger) Error(v ...interface{}) {
-	z.Logger.Sugar().Named(z.opts.Prefix).Error(v)
+	z.Logger.Sugar().Named(z.opts.Prefix).Error(v...)
 }
 
 func (z *ZapLogger) Errorf(format string, args ...interface{}) {
@@ -185,15 +396,63 @@ func (z *ZapLogger) Errorf(format string, args ...interface{}) {
 }
 
 func (z *ZapLogger) DPanic(v ...interface{}) {
-	z.Logger.Sugar().Named(z.opts.Prefix).DPanic(v)
+	z.Logger.Sugar().Named(z.opts.Prefix).DPanic(v...)
 }
 
 func (z *ZapLogger) Panic(v ...interface{}) {
-	z.Logger.Sugar().Named(z.opts.Prefix).Panic(v)
+	z.Logger.Sugar().Named(z.opts.Prefix).Panic(v...)
 }
 
 func (z *ZapLogger) Fatal(v ...interface{}) {
-	z.Logger.Sugar().Named(z.opts.Prefix).Fatal(v)
+	z.Logger.Sugar().Named(z.opts.Prefix).Fatal(v...)
+}
+
+func (z *ZapLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	z.Logger.Sugar().Named(z.opts.Prefix).Debugw(msg, toZapArgs(keysAndValues)...)
+}
+
+func (z *ZapLogger) Infow(msg string, keysAndValues ...interface{}) {
+	z.Logger.Sugar().Named(z.opts.Prefix).Infow(msg, toZapArgs(keysAndValues)...)
+}
+
+func (z *ZapLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	z.Logger.Sugar().Named(z.opts.Prefix).Warnw(msg, toZapArgs(keysAndValues)...)
+}
+
+func (z *ZapLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	z.Logger.Sugar().Named(z.opts.Prefix).Errorw(msg, toZapArgs(keysAndValues)...)
+}
+
+// With 返回附带 fields 的子 Logger
+func (z *ZapLogger) With(fields ...logger.Field) logger.Logger {
+	return &ZapLogger{
+		z.Logger.With(ToZapFields(fields...)...),
+		z.opts,
+		z.level,
+		z.closers,
+	}
+}
+
+// ToZapFields 将 logger.Field 转换为 zap.Field
+func ToZapFields(fields ...logger.Field) []zap.Field {
+	zfs := make([]zap.Field, len(fields))
+	for i, f := range fields {
+		zfs[i] = zap.Any(f.Key, f.Value)
+	}
+	return zfs
+}
+
+// toZapArgs 将 key/value 参数中的 logger.Field 转换为 zap.Field，其余保持不变交由 SugaredLogger 处理
+func toZapArgs(keysAndValues []interface{}) []interface{} {
+	args := make([]interface{}, len(keysAndValues))
+	for i, v := range keysAndValues {
+		if f, ok := v.(logger.Field); ok {
+			args[i] = zap.Any(f.Key, f.Value)
+			continue
+		}
+		args[i] = v
+	}
+	return args
 }
 
 type Config struct {
@@ -213,6 +472,12 @@ type Config struct {
 	DisableStacktrace bool `json:"disableStacktrace" yaml:"disableStacktrace"`
 	// Sampling sets a sampling policy. A nil SamplingConfig disables sampling.
 	Sampling *zap.SamplingConfig `json:"sampling" yaml:"sampling"`
+	// SamplingTick 采样的统计周期，为 0 时取 1 秒
+	SamplingTick time.Duration `json:"samplingTick" yaml:"samplingTick"`
+	// RateLimits 按日志消息限速
+	RateLimits map[string]logger.RateLimit `json:"-" yaml:"-"`
+	// AlwaysLogLevel 不低于该级别的日志不受采样与限速影响
+	AlwaysLogLevel zapcore.Level `json:"alwaysLogLevel" yaml:"alwaysLogLevel"`
 	// Encoding sets the logger's encoding. Valid values are "json" and
 	// "console", as well as any third-party encodings registered via
 	// RegisterEncoder.
@@ -220,11 +485,32 @@ type Config struct {
 
 	WriteSyncer zapcore.WriteSyncer
 
+	// Outputs 非空时替代 Encoder 和 WriteSyncer，日志同时写入每个 Output
+	Outputs []Output
+
+	// Closers 在 Logger.Close 时关闭，如日志文件
+	Closers []io.Closer
+
 	InitialFields map[string]interface{} `json:"initialFields" yaml:"initialFields"`
 }
 
+// Output 一个带独立编码与最低级别的输出
+type Output struct {
+	Encoder     zapcore.Encoder
+	WriteSyncer zapcore.WriteSyncer
+	// Level 为 nil 时只使用 Config.Level
+	Level *zapcore.Level
+}
+
 func (cfg Config) Build(opts ...zap.Option) *zap.Logger {
 	core := zapcore.NewCore(cfg.Encoder, cfg.WriteSyncer, cfg.Level)
+	if len(cfg.Outputs) > 0 {
+		cores := make([]zapcore.Core, len(cfg.Outputs))
+		for i, o := range cfg.Outputs {
+			cores[i] = zapcore.NewCore(o.Encoder, o.WriteSyncer, outputLevel{cfg.Level, o.Level})
+		}
+		core = zapcore.NewTee(cores...)
+	}
 
 	log := zap.New(core, cfg.buildOptions()...)
 	if len(opts) > 0 {
@@ -255,10 +541,8 @@ func (cfg Config) buildOptions() []zap.Option {
 		opts = append(opts, zap.AddStacktrace(stackLevel))
 	}
 
-	if cfg.Sampling != nil {
-		opts = append(opts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
-			return zapcore.NewSampler(core, time.Second, int(cfg.Sampling.Initial), int(cfg.Sampling.Thereafter))
-		}))
+	if cfg.Sampling != nil || len(cfg.RateLimits) > 0 {
+		opts = append(opts, zap.WrapCore(cfg.wrapLimits))
 	}
 
 	if len(cfg.InitialFields) > 0 {
@@ -276,3 +560,37 @@ func (cfg Config) buildOptions() []zap.Option {
 
 	return opts
 }
+
+// outputLevel 同时满足全局级别与 Output 自身的最低级别时才输出，min 为 nil 时只使用全局级别
+type outputLevel struct {
+	global zap.AtomicLevel
+	min    *zapcore.Level
+}
+
+func (l outputLevel) Enabled(lvl zapcore.Level) bool {
+	return (l.min == nil || lvl >= *l.min) && l.global.Enabled(lvl)
+}
+
+// wrapLimits 先限速再采样，AlwaysLogLevel 及以上的日志绕过两者直接写入 core
+func (cfg Config) wrapLimits(core zapcore.Core) zapcore.Core {
+	limited := core
+	if s := cfg.Sampling; s != nil {
+		tick := cfg.SamplingTick
+		if tick <= 0 {
+			tick = time.Second
+		}
+		thereafter := s.Thereafter
+		if thereafter <= 0 {
+			thereafter = math.MaxInt32
+		}
+		hook := s.Hook
+		if hook == nil {
+			hook = func(zapcore.Entry, zapcore.SamplingDecision) {}
+		}
+		limited = zapcore.NewSamplerWithOptions(limited, tick, s.Initial, thereafter, zapcore.SamplerHook(hook))
+	}
+	if len(cfg.RateLimits) > 0 {
+		limited = newRateLimitCore(limited, cfg.RateLimits)
+	}
+	return &exemptCore{Core: limited, raw: core, level: cfg.AlwaysLogLevel}
+}
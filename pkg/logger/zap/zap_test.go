@@ -1,7 +1,11 @@
 package zap
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mel2oo/juice/pkg/logger"
 
@@ -27,3 +31,118 @@ func Test_ZapLogFile(t *testing.T) {
 	log.(*ZapLogger).ReplaceGlobals()
 	zap.L().Info("hello")
 }
+
+func Test_ZapSinks(t *testing.T) {
+	var all, errs bytes.Buffer
+	log := NewZapLogger(
+		logger.WithDisableCaller(),
+		logger.WithDisableStacktrace(),
+		logger.WithTimeKey("@timestamp"),
+		logger.WithLevelKey("severity"),
+		logger.WithSink(logger.Sink{Writer: &all, Level: logger.LevelPtr(logger.InfoLevel), Encoding: logger.EncodingJSON}),
+		logger.WithSink(logger.Sink{Writer: &errs, Level: logger.LevelPtr(logger.ErrorLevel), Encoding: logger.EncodingLogfmt}),
+	)
+
+	log.Debug("debug")
+	log.Infow("info", "user_id", 1)
+	log.Errorw("failed", logger.String("reason", "bad input"))
+
+	lines := strings.Split(strings.TrimSpace(all.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected json output %q", all.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["severity"] != "info" || entry["msg"] != "info" || entry["user_id"] != float64(1) || entry["@timestamp"] == nil {
+		t.Fatalf("unexpected json entry %v", entry)
+	}
+
+	line := strings.TrimSpace(errs.String())
+	if strings.Contains(line, "\n") || !strings.Contains(line, `severity=error`) || !strings.Contains(line, `msg=failed reason="bad input"`) {
+		t.Fatalf("unexpected logfmt output %q", errs.String())
+	}
+}
+
+func Test_ZapSampling(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewZapLogger(
+		logger.WithDisableCaller(),
+		logger.WithDisableStacktrace(),
+		logger.WithSink(logger.Sink{Writer: &buf, Level: logger.LevelPtr(logger.DebugLevel), Encoding: logger.EncodingLogfmt}),
+		logger.WithSampling(time.Minute, 2, 0),
+		logger.WithRateLimit("noisy", time.Hour, 1),
+	)
+
+	sampled := logger.DroppedCount(logger.DropSampled)
+	limited := logger.DroppedCount(logger.DropRateLimited)
+	for i := 0; i < 5; i++ {
+		log.Info("sampled")
+		log.With(logger.Int("i", i)).Warn("noisy")
+		log.Error("sampled")
+	}
+
+	out := buf.String()
+	if n := strings.Count(out, "msg=sampled"); n != 7 {
+		t.Fatalf("expected 2 sampled infos and 5 errors, got %d:\n%s", n, out)
+	}
+	if n := strings.Count(out, "msg=noisy"); n != 1 {
+		t.Fatalf("expected 1 rate limited entry, got %d:\n%s", n, out)
+	}
+	if d := logger.DroppedCount(logger.DropSampled) - sampled; d != 3 {
+		t.Fatalf("unexpected sampled drops %d", d)
+	}
+	if d := logger.DroppedCount(logger.DropRateLimited) - limited; d != 4 {
+		t.Fatalf("unexpected rate limited drops %d", d)
+	}
+}
+
+func Test_ToZapKeepsPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewZapLogger(
+		logger.WithPrefix("juice"),
+		logger.WithDisableCaller(),
+		logger.WithDisableStacktrace(),
+		logger.WithSink(logger.Sink{Writer: &buf, Level: logger.LevelPtr(logger.InfoLevel), Encoding: logger.EncodingJSON}),
+	)
+
+	ToZap(log).Info("bridged")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["logger"] != "juice " {
+		t.Fatalf("expected logger name juice, got %v", entry)
+	}
+}
+
+func Test_ZapSinkInheritsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewZapLogger(
+		logger.WithLevel(logger.WarnLevel),
+		logger.WithDisableCaller(),
+		logger.WithDisableStacktrace(),
+		logger.WithSink(logger.Sink{Writer: &buf, Encoding: logger.EncodingLogfmt}),
+	)
+
+	log.Info("hidden")
+	log.Warn("shown")
+	log.SetLevel(logger.DebugLevel)
+	log.Debug("debug")
+
+	out := buf.String()
+	if strings.Contains(out, "msg=hidden") || !strings.Contains(out, "msg=shown") || !strings.Contains(out, "msg=debug") {
+		t.Fatalf("unexpected output %q", out)
+	}
+}
+
+func Test_ZapInvalidConfig(t *testing.T) {
+	if _, err := New(logger.WithEncoding("xml")); err == nil {
+		t.Fatal("expected unknown encoding error")
+	}
+	if _, err := New(logger.WithSink(logger.Sink{Output: "syslog+udp://%zz"})); err == nil {
+		t.Fatal("expected invalid sink error")
+	}
+}
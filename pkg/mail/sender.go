@@ -0,0 +1,298 @@
+package mail
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/pkg/logger/zap"
+	"github.com/pkg/errors"
+	"gopkg.in/gomail.v2"
+)
+
+const (
+	// DefaultQueueSize 待发送队列长度
+	DefaultQueueSize = 100
+	// DefaultWorkers 并发发送数，每个 worker 持有一个 SMTP 连接
+	DefaultWorkers = 1
+	// DefaultRetryTimes 发送失败后最多重试次数
+	DefaultRetryTimes = 3
+	// DefaultRetryDelay 首次重试前的等待时间，之后每次翻倍
+	DefaultRetryDelay = time.Second
+	// DefaultIdleTimeout 连接空闲超过该时间后关闭，有新邮件时再重新连接
+	DefaultIdleTimeout = time.Second * 30
+)
+
+var (
+	ErrQueueFull  = errors.New("mail queue is full")
+	ErrClosed     = errors.New("mail sender is closed")
+	ErrNilMessage = errors.New("mail message is nil")
+)
+
+// Attachment 邮件附件
+type Attachment struct {
+	Filename    string
+	ContentType string // 为空时根据文件名推断
+	Content     []byte
+}
+
+// Message 异步发送的邮件
+type Message struct {
+	To          []string // 为空时使用 Options.MailTo
+	Cc          []string
+	Bcc         []string
+	Subject     string
+	HTML        string
+	Text        string // 纯文本内容，与 HTML 同时设置时作为 multipart/alternative 的备选
+	Attachments []*Attachment
+}
+
+type SenderOption func(*Sender)
+
+// WithQueueSize 设置队列长度，队列满时 Enqueue 返回 ErrQueueFull
+func WithQueueSize(size int) SenderOption {
+	return func(s *Sender) {
+		s.queueSize = size
+	}
+}
+
+// WithWorkers 设置并发发送数(即 SMTP 连接数)
+func WithWorkers(n int) SenderOption {
+	return func(s *Sender) {
+		s.workers = n
+	}
+}
+
+// WithRetry 设置失败重试次数与首次重试的等待时间(指数退避)
+func WithRetry(times int, delay time.Duration) SenderOption {
+	return func(s *Sender) {
+		s.retryTimes = times
+		s.retryDelay = delay
+	}
+}
+
+// WithIdleTimeout 设置连接空闲关闭时间，非正数将被忽略
+func WithIdleTimeout(timeout time.Duration) SenderOption {
+	return func(s *Sender) {
+		if timeout > 0 {
+			s.idleTimeout = timeout
+		}
+	}
+}
+
+// WithLogger 设置 logger，用于记录最终发送失败的邮件
+func WithLogger(log logger.Logger) SenderOption {
+	return func(s *Sender) {
+		s.log = log
+	}
+}
+
+// Sender 复用 SMTP 连接的异步发送器；实现了 transport.Server，可直接交给 juice.Server 管理，
+// Stop 时会把队列中剩余的邮件发送完再返回。
+type Sender struct {
+	opts        Options
+	dialer      *gomail.Dialer
+	queueSize   int
+	workers     int
+	retryTimes  int
+	retryDelay  time.Duration
+	idleTimeout time.Duration
+	log         logger.Logger
+
+	mux    sync.RWMutex
+	queue  chan *Message
+	closed bool
+	wg     sync.WaitGroup
+	done   chan struct{}
+}
+
+func NewSender(o *Options, opts ...SenderOption) *Sender {
+	s := &Sender{
+		opts:        *o,
+		dialer:      gomail.NewDialer(o.MailHost, o.MailPort, o.MailUser, o.MailPass),
+		queueSize:   DefaultQueueSize,
+		workers:     DefaultWorkers,
+		retryTimes:  DefaultRetryTimes,
+		retryDelay:  DefaultRetryDelay,
+		idleTimeout: DefaultIdleTimeout,
+		log:         zap.DefaultLogger,
+		done:        make(chan struct{}),
+	}
+
+	for _, f := range opts {
+		f(s)
+	}
+
+	s.queue = make(chan *Message, s.queueSize)
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+
+	return s
+}
+
+// Enqueue 将邮件放入发送队列，不会阻塞
+func (s *Sender) Enqueue(m *Message) error {
+	if m == nil {
+		return ErrNilMessage
+	}
+
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	select {
+	case s.queue <- m:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Send 以 HTML 正文异步发送给默认收件人，与 httpclient.AlarmObject、notifier.Notifier 兼容
+func (s *Sender) Send(subject, body string) error {
+	return s.Enqueue(&Message{
+		Subject: subject,
+		HTML:    body,
+	})
+}
+
+// Start 阻塞直至 Stop 被调用
+func (s *Sender) Start() error {
+	<-s.done
+	return nil
+}
+
+// Stop 停止接收新邮件，等待队列中的邮件发送完毕
+func (s *Sender) Stop() error {
+	s.mux.Lock()
+	if s.closed {
+		s.mux.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mux.Unlock()
+
+	s.wg.Wait()
+	close(s.done)
+	return nil
+}
+
+func (s *Sender) work() {
+	defer s.wg.Done()
+
+	var (
+		conn gomail.SendCloser
+		err  error
+	)
+	closeConn := func() {
+		if conn != nil {
+			conn.Close()
+			conn = nil
+		}
+	}
+	defer closeConn()
+
+	idle := time.NewTimer(s.idleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case m, ok := <-s.queue:
+			if !ok {
+				return
+			}
+
+			msg := s.build(m)
+			delay := s.retryDelay
+			for k := 0; k <= s.retryTimes; k++ {
+				if k > 0 {
+					time.Sleep(delay)
+					delay *= 2
+				}
+
+				if conn == nil {
+					if conn, err = s.dialer.Dial(); err != nil {
+						conn = nil
+						continue
+					}
+				}
+
+				if err = gomail.Send(conn, msg); err == nil {
+					break
+				}
+				// 连接可能已失效，下次重试时重新连接
+				closeConn()
+			}
+
+			if err != nil && s.log != nil {
+				s.log.Errorf("mail send [%s] to %v err: %v", m.Subject, m.To, err)
+			}
+
+			if !idle.Stop() {
+				select {
+				case <-idle.C:
+				default:
+				}
+			}
+			idle.Reset(s.idleTimeout)
+
+		case <-idle.C:
+			closeConn()
+			idle.Reset(s.idleTimeout)
+		}
+	}
+}
+
+func (s *Sender) build(m *Message) *gomail.Message {
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", s.opts.MailUser)
+
+	to := m.To
+	if len(to) == 0 {
+		to = strings.Split(s.opts.MailTo, ",")
+	}
+	msg.SetHeader("To", to...)
+	if len(m.Cc) > 0 {
+		msg.SetHeader("Cc", m.Cc...)
+	}
+	if len(m.Bcc) > 0 {
+		msg.SetHeader("Bcc", m.Bcc...)
+	}
+	msg.SetHeader("Subject", m.Subject)
+
+	switch {
+	case m.Text != "" && m.HTML != "":
+		msg.SetBody("text/plain", m.Text)
+		msg.AddAlternative("text/html", m.HTML)
+	case m.HTML != "":
+		msg.SetBody("text/html", m.HTML)
+	default:
+		msg.SetBody("text/plain", m.Text)
+	}
+
+	for _, a := range m.Attachments {
+		content := a.Content
+		settings := []gomail.FileSetting{
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(content)
+				return err
+			}),
+		}
+		if a.ContentType != "" {
+			settings = append(settings, gomail.SetHeader(map[string][]string{
+				"Content-Type": {a.ContentType},
+			}))
+		}
+		msg.Attach(a.Filename, settings...)
+	}
+
+	return msg
+}
@@ -0,0 +1,171 @@
+package mail
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// smtpStandIn 极简 SMTP 服务，记录收到的邮件并可让前 failures 次 DATA 失败
+type smtpStandIn struct {
+	lis      net.Listener
+	mux      sync.Mutex
+	failures int
+	dials    int
+	messages []string
+}
+
+func newSMTPStandIn(t *testing.T, failures int) *smtpStandIn {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &smtpStandIn{lis: lis, failures: failures}
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			s.mux.Lock()
+			s.dials++
+			s.mux.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "DATA"):
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+
+			s.mux.Lock()
+			if s.failures > 0 {
+				s.failures--
+				s.mux.Unlock()
+				reply("451 try again")
+				continue
+			}
+			s.messages = append(s.messages, data.String())
+			s.mux.Unlock()
+			reply("250 queued")
+		case strings.HasPrefix(cmd, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *smtpStandIn) options() *Options {
+	addr := s.lis.Addr().(*net.TCPAddr)
+	return &Options{
+		MailHost: "127.0.0.1",
+		MailPort: addr.Port,
+		MailUser: "from@example.com",
+		MailTo:   "to@example.com",
+	}
+}
+
+func (s *smtpStandIn) received() ([]string, int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return append([]string(nil), s.messages...), s.dials
+}
+
+func TestSenderFlushOnStop(t *testing.T) {
+	srv := newSMTPStandIn(t, 1)
+	defer srv.lis.Close()
+
+	sender := NewSender(srv.options(), WithRetry(2, time.Millisecond*10))
+	for i := 0; i < 5; i++ {
+		if err := sender.Send("subject "+strconv.Itoa(i), "<b>body</b>"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sender.Enqueue(&Message{
+		Cc:          []string{"cc@example.com"},
+		Subject:     "with attachment",
+		HTML:        "<b>html</b>",
+		Text:        "text",
+		Attachments: []*Attachment{{Filename: "a.txt", Content: []byte("hello")}},
+	})
+	sender.Stop()
+
+	messages, dials := srv.received()
+	if len(messages) != 6 {
+		t.Fatalf("expected 6 messages, got %d", len(messages))
+	}
+	// 失败一次后重连，其余邮件复用连接
+	if dials != 2 {
+		t.Fatalf("expected 2 dials, got %d", dials)
+	}
+
+	last := messages[5]
+	for _, want := range []string{"Cc: cc@example.com", "multipart/alternative", "a.txt"} {
+		if !strings.Contains(last, want) {
+			t.Fatalf("message missing %q:\n%s", want, last)
+		}
+	}
+
+	if err := sender.Send("late", "body"); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestSenderQueueFull(t *testing.T) {
+	sender := NewSender(&Options{MailHost: "127.0.0.1", MailPort: 1}, WithQueueSize(1), WithWorkers(0))
+	sender.Send("a", "body")
+	if err := sender.Send("b", "body"); err != ErrQueueFull {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+}
+
+func TestSenderNilMessage(t *testing.T) {
+	sender := NewSender(&Options{MailHost: "127.0.0.1", MailPort: 1}, WithWorkers(0))
+	if err := sender.Enqueue(nil); err != ErrNilMessage {
+		t.Fatalf("expected ErrNilMessage, got %v", err)
+	}
+}
+
+func TestSenderIgnoreNonPositiveIdleTimeout(t *testing.T) {
+	for _, timeout := range []time.Duration{0, -time.Second} {
+		sender := NewSender(&Options{MailHost: "127.0.0.1", MailPort: 1}, WithWorkers(0), WithIdleTimeout(timeout))
+		if sender.idleTimeout != DefaultIdleTimeout {
+			t.Fatalf("idle timeout %v: expected default, got %v", timeout, sender.idleTimeout)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultDingTalkTemplate 钉钉机器人的默认请求体(markdown 消息)
+const DefaultDingTalkTemplate = `{"msgtype":"markdown","markdown":{"title":{{json .Subject}},"text":{{json (printf "### %s\n\n%s" .Subject .Body)}}}}`
+
+// NewDingTalk 钉钉自定义机器人，url 为包含 access_token 的完整 webhook 地址；
+// 机器人开启加签时需通过 WithSecret 设置密钥。
+func NewDingTalk(url string, opts ...Option) (Notifier, error) {
+	w, o, err := newWebhook(url, DefaultDingTalkTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret := o.secret; secret != "" {
+		w.sign = func(u string) string {
+			return dingTalkSign(u, secret, time.Now())
+		}
+	}
+	w.check = checkRobotReply
+	return w, nil
+}
+
+func dingTalkSign(rawURL, secret string, now time.Time) string {
+	timestamp := strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+	return rawURL + sep + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(sign)
+}
+
+// checkRobotReply 钉钉、企业微信机器人在 http 200 时通过 errcode 返回实际结果
+func checkRobotReply(body []byte) error {
+	reply := &struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}{}
+	if err := json.Unmarshal(body, reply); err != nil {
+		return errors.Wrapf(err, "unmarshal robot reply `%s` err", string(body))
+	}
+
+	if reply.ErrCode != 0 {
+		return errors.Errorf("robot return errcode: %d errmsg: %s", reply.ErrCode, reply.ErrMsg)
+	}
+	return nil
+}
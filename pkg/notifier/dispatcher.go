@@ -0,0 +1,193 @@
+package notifier
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultWindow 相同指纹的告警在该时间窗口内只发送一次
+	DefaultWindow = time.Minute * 5
+	// DefaultRateEvery 全局限流，默认每 10 秒最多发送一条
+	DefaultRateEvery = time.Second * 10
+	// DefaultRateBurst 全局限流的突发数
+	DefaultRateBurst = 10
+)
+
+var _ FingerprintNotifier = (*Dispatcher)(nil)
+
+type DispatcherOption func(*Dispatcher)
+
+// WithWindow 设置去重窗口
+func WithWindow(window time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.window = window
+	}
+}
+
+// WithRateLimit 设置全局限流，every 为平均发送间隔
+func WithRateLimit(every time.Duration, burst int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.limiter = rate.NewLimiter(rate.Every(every), burst)
+	}
+}
+
+// WithLogger 设置 logger，用于记录异步发送汇总时的错误
+func WithLogger(log logger.Logger) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.log = log
+	}
+}
+
+// Dispatcher 对告警按指纹去重：窗口内的首条告警立即发送，之后的重复告警只计数，
+// 窗口结束时若有被抑制的告警则发送一条带次数的汇总；所有发送都受全局限流约束。
+type Dispatcher struct {
+	dropped uint64
+	target  Notifier
+	window  time.Duration
+	limiter *rate.Limiter
+	log     logger.Logger
+
+	mux    sync.Mutex
+	groups map[string]*group
+	closed bool
+}
+
+type group struct {
+	subject    string
+	body       string
+	first      time.Time
+	last       time.Time
+	suppressed int
+	unsent     bool // 首条告警被全局限流，未发送
+	timer      *time.Timer
+}
+
+func NewDispatcher(target Notifier, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		target:  target,
+		window:  DefaultWindow,
+		limiter: rate.NewLimiter(rate.Every(DefaultRateEvery), DefaultRateBurst),
+		groups:  make(map[string]*group),
+	}
+
+	for _, o := range opts {
+		o(d)
+	}
+
+	return d
+}
+
+// Format 与被包装的 Notifier 一致
+func (d *Dispatcher) Format() Format {
+	return FormatOf(d.target)
+}
+
+// Send 以 subject 作为指纹发送告警
+func (d *Dispatcher) Send(subject, body string) error {
+	return d.SendWithFingerprint(subject, subject, body)
+}
+
+// SendWithFingerprint 按指定指纹发送告警；被去重或限流的告警返回 nil
+func (d *Dispatcher) SendWithFingerprint(fingerprint, subject, body string) error {
+	now := time.Now()
+
+	d.mux.Lock()
+	if d.closed {
+		d.mux.Unlock()
+		return d.send(subject, body)
+	}
+
+	if g, ok := d.groups[fingerprint]; ok {
+		g.suppressed++
+		g.body = body
+		g.last = now
+		d.mux.Unlock()
+		return nil
+	}
+
+	g := &group{
+		subject: subject,
+		body:    body,
+		first:   now,
+		last:    now,
+		// 首条被限流时在窗口结束时随汇总再尝试发送；与分组的创建在同一临界区内决定，避免 flush 先于计数执行
+		unsent: !d.limiter.Allow(),
+	}
+	g.timer = time.AfterFunc(d.window, func() {
+		d.flush(fingerprint)
+	})
+	d.groups[fingerprint] = g
+	d.mux.Unlock()
+
+	if g.unsent {
+		return nil
+	}
+	return d.target.Send(subject, body)
+}
+
+// Dropped 因全局限流而丢弃的告警数
+func (d *Dispatcher) Dropped() uint64 {
+	return atomic.LoadUint64(&d.dropped)
+}
+
+// Close 立即发送所有窗口中待发送的汇总，之后的告警不再去重
+func (d *Dispatcher) Close() error {
+	d.mux.Lock()
+	d.closed = true
+	fingerprints := make([]string, 0, len(d.groups))
+	for fingerprint, g := range d.groups {
+		g.timer.Stop()
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	d.mux.Unlock()
+
+	for _, fingerprint := range fingerprints {
+		d.flush(fingerprint)
+	}
+	return nil
+}
+
+func (d *Dispatcher) flush(fingerprint string) {
+	d.mux.Lock()
+	g, ok := d.groups[fingerprint]
+	if ok {
+		delete(d.groups, fingerprint)
+	}
+	d.mux.Unlock()
+
+	if !ok || (g.suppressed == 0 && !g.unsent) {
+		return
+	}
+
+	// 首条已发送时汇总其后的重复次数，否则汇总窗口内的全部次数
+	summary, count := "重复", g.suppressed
+	if g.unsent {
+		summary, count = "共", g.suppressed+1
+	}
+	subject := fmt.Sprintf("%s (%s %d 次)", g.subject, summary, count)
+	body := fmt.Sprintf("%s ~ %s 期间%s %d 次，最近一次内容如下:\n\n%s",
+		g.first.Format(time.RFC3339),
+		g.last.Format(time.RFC3339),
+		summary,
+		count,
+		g.body,
+	)
+
+	if err := d.send(subject, body); err != nil && d.log != nil {
+		d.log.Errorf("notifier send digest err: %v", err)
+	}
+}
+
+func (d *Dispatcher) send(subject, body string) error {
+	if !d.limiter.Allow() {
+		atomic.AddUint64(&d.dropped, 1)
+		return nil
+	}
+	return d.target.Send(subject, body)
+}
@@ -0,0 +1,81 @@
+package notifier
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	mux      sync.Mutex
+	subjects []string
+}
+
+func (r *recorder) Send(subject, body string) error {
+	r.mux.Lock()
+	r.subjects = append(r.subjects, subject)
+	r.mux.Unlock()
+	return nil
+}
+
+func (r *recorder) sent() []string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]string(nil), r.subjects...)
+}
+
+func TestDispatcherDigest(t *testing.T) {
+	r := &recorder{}
+	d := NewDispatcher(r, WithWindow(time.Millisecond*50))
+
+	for i := 0; i < 3; i++ {
+		d.SendWithFingerprint("GET /panic boom", "panic", "body")
+	}
+	d.SendWithFingerprint("GET /other boom", "other", "body")
+
+	if got := r.sent(); len(got) != 2 {
+		t.Fatalf("expected 2 immediate alerts, got %v", got)
+	}
+
+	time.Sleep(time.Millisecond * 150)
+
+	got := r.sent()
+	if len(got) != 3 || got[2] != "panic (重复 2 次)" {
+		t.Fatalf("expected digest for suppressed alerts, got %v", got)
+	}
+}
+
+func TestDispatcherRateLimit(t *testing.T) {
+	r := &recorder{}
+	d := NewDispatcher(r, WithWindow(time.Hour), WithRateLimit(time.Hour, 1))
+
+	d.Send("a", "body")
+	d.Send("b", "body")
+	if got := r.sent(); len(got) != 1 {
+		t.Fatalf("expected 1 alert within burst, got %v", got)
+	}
+
+	d.Close()
+	if d.Dropped() != 1 {
+		t.Fatalf("expected 1 dropped digest, got %d", d.Dropped())
+	}
+	if got := r.sent(); len(got) != 1 || !strings.HasPrefix(got[0], "a") {
+		t.Fatalf("unexpected alerts %v", got)
+	}
+}
+
+func TestDispatcherUnsentFirstAlert(t *testing.T) {
+	r := &recorder{}
+	d := NewDispatcher(r, WithWindow(time.Hour), WithRateLimit(time.Millisecond*20, 1))
+
+	d.Send("a", "body")
+	d.Send("b", "body")
+	d.Send("b", "body")
+	time.Sleep(time.Millisecond * 30)
+
+	d.Close()
+	if got := r.sent(); len(got) != 2 || got[1] != "b (共 2 次)" {
+		t.Fatalf("expected digest counting the unsent first alert, got %v", got)
+	}
+}
@@ -0,0 +1,25 @@
+package notifier
+
+import "github.com/mel2oo/juice/pkg/mail"
+
+var _ Notifier = (*mailNotifier)(nil)
+
+type mailNotifier struct {
+	opts mail.Options
+}
+
+// NewMail 通过 SMTP 邮件发送通知，每次发送都会复制 opts，可并发使用
+func NewMail(opts *mail.Options) Notifier {
+	return &mailNotifier{opts: *opts}
+}
+
+func (m *mailNotifier) Format() Format {
+	return FormatHTML
+}
+
+func (m *mailNotifier) Send(subject, body string) error {
+	opts := m.opts
+	opts.Subject = subject
+	opts.Body = body
+	return mail.Send(&opts)
+}
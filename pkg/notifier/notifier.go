@@ -0,0 +1,161 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultTimeout 单次通知请求的超时时间
+const DefaultTimeout = time.Second * 5
+
+// Notifier 告警通知，与 httpclient.AlarmObject 兼容
+type Notifier interface {
+	Send(subject, body string) error
+}
+
+// FingerprintNotifier 支持按指纹去重的 Notifier，如 Dispatcher
+type FingerprintNotifier interface {
+	Notifier
+	SendWithFingerprint(fingerprint, subject, body string) error
+}
+
+// Message 模板渲染时可用的数据
+type Message struct {
+	Subject string
+	Body    string
+}
+
+type Option func(*options)
+
+type options struct {
+	template string
+	client   *http.Client
+	header   http.Header
+	secret   string
+}
+
+// WithTemplate 自定义请求体模板(text/template)，可使用 .Subject .Body 以及 json 函数
+func WithTemplate(tpl string) Option {
+	return func(o *options) {
+		o.template = tpl
+	}
+}
+
+// WithClient 自定义 http.Client
+func WithClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithHeader 设置请求 header，可以调用多次设置多对key-value
+func WithHeader(key, value string) Option {
+	return func(o *options) {
+		o.header.Set(key, value)
+	}
+}
+
+// WithSecret 设置机器人的加签密钥(钉钉)
+func WithSecret(secret string) Option {
+	return func(o *options) {
+		o.secret = secret
+	}
+}
+
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		raw, err := json.Marshal(v)
+		return string(raw), err
+	},
+}
+
+// webhook 以 POST JSON 的方式推送消息，各类机器人只是模板、签名和响应校验不同
+type webhook struct {
+	url    string
+	tpl    *template.Template
+	client *http.Client
+	header http.Header
+	sign   func(url string) string
+	check  func(body []byte) error
+	format Format
+}
+
+func newWebhook(url, defaultTemplate string, opts []Option) (*webhook, *options, error) {
+	if url == "" {
+		return nil, nil, errors.New("url required")
+	}
+
+	o := &options{
+		template: defaultTemplate,
+		client:   &http.Client{Timeout: DefaultTimeout},
+		header:   make(http.Header),
+	}
+	for _, f := range opts {
+		f(o)
+	}
+	o.header.Set("Content-Type", "application/json; charset=utf-8")
+
+	tpl, err := template.New("notifier").Funcs(funcs).Parse(o.template)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parse notifier template err")
+	}
+
+	return &webhook{
+		url:    url,
+		tpl:    tpl,
+		client: o.client,
+		header: o.header,
+		format: FormatMarkdown,
+	}, o, nil
+}
+
+// Format 聊天机器人使用 markdown 正文，通用 webhook 使用纯文本
+func (w *webhook) Format() Format {
+	return w.format
+}
+
+func (w *webhook) Send(subject, body string) error {
+	buf := new(bytes.Buffer)
+	if err := w.tpl.Execute(buf, &Message{Subject: subject, Body: body}); err != nil {
+		return errors.Wrap(err, "execute notifier template err")
+	}
+
+	url := w.url
+	if w.sign != nil {
+		url = w.sign(url)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, buf)
+	if err != nil {
+		return errors.Wrapf(err, "new request [POST %s] err", w.url)
+	}
+	for k, v := range w.header {
+		req.Header[k] = v
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "do request [POST %s] err", w.url)
+	}
+	defer resp.Body.Close()
+
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "read resp body from [POST %s] err", w.url)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("do [POST %s] return code: %d message: %s", w.url, resp.StatusCode, string(raw))
+	}
+
+	if w.check != nil {
+		return w.check(raw)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newStandIn(t *testing.T, reply string, got *map[string]interface{}, query *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(raw, got); err != nil {
+			t.Errorf("invalid json payload %s: %v", raw, err)
+		}
+		if query != nil {
+			*query = r.URL.RawQuery
+		}
+		w.Write([]byte(reply))
+	}))
+}
+
+func TestWebhook(t *testing.T) {
+	var got map[string]interface{}
+	srv := newStandIn(t, "ok", &got, nil)
+	defer srv.Close()
+
+	n, err := NewWebhook(srv.URL, WithTemplate(`{"title":{{json .Subject}},"content":{{json .Body}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Send("subject", `body "quoted"`); err != nil {
+		t.Fatal(err)
+	}
+	if got["title"] != "subject" || got["content"] != `body "quoted"` {
+		t.Fatalf("unexpected payload %v", got)
+	}
+}
+
+func TestSlack(t *testing.T) {
+	var got map[string]interface{}
+	srv := newStandIn(t, "ok", &got, nil)
+	defer srv.Close()
+
+	n, _ := NewSlack(srv.URL)
+	if err := n.Send("subject", "body"); err != nil {
+		t.Fatal(err)
+	}
+	if got["text"] != "*subject*\nbody" {
+		t.Fatalf("unexpected payload %v", got)
+	}
+}
+
+func TestDingTalk(t *testing.T) {
+	var (
+		got   map[string]interface{}
+		query string
+	)
+	srv := newStandIn(t, `{"errcode":0,"errmsg":"ok"}`, &got, &query)
+	defer srv.Close()
+
+	n, _ := NewDingTalk(srv.URL+"?access_token=t", WithSecret("SEC"))
+	if err := n.Send("subject", "body"); err != nil {
+		t.Fatal(err)
+	}
+	if got["msgtype"] != "markdown" {
+		t.Fatalf("unexpected payload %v", got)
+	}
+	if !strings.Contains(query, "access_token=t&timestamp=") || !strings.Contains(query, "&sign=") {
+		t.Fatalf("unexpected query %s", query)
+	}
+
+	failed := newStandIn(t, `{"errcode":310000,"errmsg":"sign not match"}`, &got, nil)
+	defer failed.Close()
+
+	n, _ = NewDingTalk(failed.URL)
+	if err := n.Send("subject", "body"); err == nil {
+		t.Fatal("expected errcode error")
+	}
+}
+
+func TestWeCom(t *testing.T) {
+	var got map[string]interface{}
+	srv := newStandIn(t, `{"errcode":0,"errmsg":"ok"}`, &got, nil)
+	defer srv.Close()
+
+	n, _ := NewWeCom(srv.URL)
+	if err := n.Send("subject", "body"); err != nil {
+		t.Fatal(err)
+	}
+	markdown, _ := got["markdown"].(map[string]interface{})
+	if markdown["content"] != "### subject\nbody" {
+		t.Fatalf("unexpected payload %v", got)
+	}
+}
+
+func TestRegistryRender(t *testing.T) {
+	r := NewRegistry()
+	alert := &Alert{
+		TraceID: "t1",
+		Method:  "GET",
+		URL:     "/panic",
+		Header:  RedactHeader(http.Header{"Authorization": {"Bearer x"}, "Accept": {"*/*"}}),
+		Error:   "boom",
+	}
+
+	subject, body, err := r.Render(EventPanic, "", FormatMarkdown, alert)
+	if err != nil || subject != "[系统异常]-/panic" || !strings.Contains(body, "**错误**: boom") {
+		t.Fatalf("unexpected render %q %q %v", subject, body, err)
+	}
+
+	if err := r.Register(EventPanic, LocaleEN, &Template{Subject: "PANIC {{.TraceID}}"}); err != nil {
+		t.Fatal(err)
+	}
+	subject, body, err = r.Render(EventPanic, LocaleEN, FormatHTML, alert)
+	if err != nil || subject != "PANIC t1" || !strings.Contains(body, "Authorization: ******") {
+		t.Fatalf("unexpected render %q %v", subject, err)
+	}
+
+	// 未注册的语言回退到默认语言
+	if subject, _, _ = r.Render(EventPanic, "ja", FormatHTML, alert); subject != "[系统异常]-/panic" {
+		t.Fatalf("unexpected fallback subject %q", subject)
+	}
+
+	_, body, err = r.Render(EventPanic, "", FormatText, alert)
+	if err != nil || !strings.Contains(body, "错误: boom") || strings.Contains(body, "**") {
+		t.Fatalf("unexpected text render %q %v", body, err)
+	}
+	if w, _ := NewWebhook("http://example.com"); FormatOf(w) != FormatText {
+		t.Fatalf("generic webhook should use plain text, got %s", FormatOf(w))
+	}
+}
@@ -0,0 +1,281 @@
+package notifier
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/notifier/templates"
+	"github.com/pkg/errors"
+)
+
+// Event 告警事件类型
+type Event string
+
+const (
+	EventPanic           Event = "panic"
+	EventOutboundFailure Event = "outbound_failure"
+	EventCircuitOpen     Event = "circuit_open"
+)
+
+// Format 告警正文格式
+type Format string
+
+const (
+	FormatHTML     Format = "html"     // 邮件
+	FormatMarkdown Format = "markdown" // 聊天机器人
+	FormatText     Format = "text"     // 通用 webhook
+)
+
+const (
+	LocaleZhCN = "zh-CN"
+	LocaleEN   = "en"
+)
+
+// DefaultRegistry 内置 panic、调用失败、熔断三类事件的中英文模板
+var DefaultRegistry = NewRegistry()
+
+// RedactedHeaders 渲染告警时会被脱敏的 header
+var RedactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"Proxy-Authorization",
+	"X-Api-Key",
+	"X-Signature",
+}
+
+// Alert 模板渲染的数据，字段按事件类型选择性填充
+type Alert struct {
+	Title    string
+	TraceID  string
+	UserID   int64
+	UserName string
+	Method   string
+	Host     string
+	Route    string
+	URL      string
+	Header   map[string]string
+	HTTPCode int
+	Body     string
+	Error    string
+	Stack    string
+	Fields   map[string]interface{}
+	Time     time.Time
+}
+
+// Year 用于模板页脚
+func (a *Alert) Year() int {
+	if a.Time.IsZero() {
+		return time.Now().Year()
+	}
+	return a.Time.Year()
+}
+
+// RedactHeader 将 header 展开为单值 map，RedactedHeaders 及 extra 中的 key 替换为 ******
+func RedactHeader(header http.Header, extra ...string) map[string]string {
+	redacted := make(map[string]bool, len(RedactedHeaders)+len(extra))
+	for _, k := range RedactedHeaders {
+		redacted[http.CanonicalHeaderKey(k)] = true
+	}
+	for _, k := range extra {
+		redacted[http.CanonicalHeaderKey(k)] = true
+	}
+
+	out := make(map[string]string, len(header))
+	for k, v := range header {
+		if redacted[http.CanonicalHeaderKey(k)] {
+			out[k] = "******"
+			continue
+		}
+		out[k] = strings.Join(v, ", ")
+	}
+	return out
+}
+
+// Template 某个事件在某个语言下的模板，Subject/Markdown/Text 为 text/template，HTML 为 html/template
+type Template struct {
+	Subject  string
+	HTML     string
+	Markdown string
+	Text     string
+}
+
+type compiled struct {
+	subject  *template.Template
+	html     *htmltemplate.Template
+	markdown *template.Template
+	text     *template.Template
+}
+
+// Registry 按事件类型和语言管理告警模板，并发安全
+type Registry struct {
+	mux       sync.RWMutex
+	locale    string
+	templates map[Event]map[string]*compiled
+}
+
+func NewRegistry() *Registry {
+	r := &Registry{
+		locale:    LocaleZhCN,
+		templates: make(map[Event]map[string]*compiled),
+	}
+
+	r.MustRegister(EventPanic, LocaleZhCN, &Template{
+		Subject:  templates.PanicSubject,
+		HTML:     templates.PanicMail,
+		Markdown: templates.PanicMarkdown,
+		Text:     templates.PanicText,
+	})
+	r.MustRegister(EventPanic, LocaleEN, &Template{
+		Subject:  templates.PanicSubjectEN,
+		HTML:     templates.PanicMailEN,
+		Markdown: templates.PanicMarkdownEN,
+		Text:     templates.PanicTextEN,
+	})
+	r.MustRegister(EventOutboundFailure, LocaleZhCN, &Template{
+		Subject:  templates.OutboundFailureSubject,
+		HTML:     templates.OutboundFailureMail,
+		Markdown: templates.OutboundFailureMarkdown,
+		Text:     templates.OutboundFailureText,
+	})
+	r.MustRegister(EventOutboundFailure, LocaleEN, &Template{
+		Subject:  templates.OutboundFailureSubjectEN,
+		HTML:     templates.OutboundFailureMailEN,
+		Markdown: templates.OutboundFailureMarkdownEN,
+		Text:     templates.OutboundFailureTextEN,
+	})
+	r.MustRegister(EventCircuitOpen, LocaleZhCN, &Template{
+		Subject:  templates.CircuitOpenSubject,
+		HTML:     templates.CircuitOpenMail,
+		Markdown: templates.CircuitOpenMarkdown,
+		Text:     templates.CircuitOpenText,
+	})
+	r.MustRegister(EventCircuitOpen, LocaleEN, &Template{
+		Subject:  templates.CircuitOpenSubjectEN,
+		HTML:     templates.CircuitOpenMailEN,
+		Markdown: templates.CircuitOpenMarkdownEN,
+		Text:     templates.CircuitOpenTextEN,
+	})
+
+	return r
+}
+
+// SetLocale 设置默认语言，Render 未指定语言时使用
+func (r *Registry) SetLocale(locale string) *Registry {
+	r.mux.Lock()
+	r.locale = locale
+	r.mux.Unlock()
+	return r
+}
+
+// Register 注册或覆盖模板，tpl 中为空的部分沿用该事件已注册的模板
+func (r *Registry) Register(event Event, locale string, tpl *Template) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	next := &compiled{}
+	if c := r.lookup(event, locale); c != nil {
+		*next = *c
+	}
+
+	var err error
+	if tpl.Subject != "" {
+		if next.subject, err = template.New("subject").Parse(tpl.Subject); err != nil {
+			return errors.Wrapf(err, "parse %s subject template err", event)
+		}
+	}
+	if tpl.HTML != "" {
+		if next.html, err = htmltemplate.New("html").Parse(tpl.HTML); err != nil {
+			return errors.Wrapf(err, "parse %s html template err", event)
+		}
+	}
+	if tpl.Markdown != "" {
+		if next.markdown, err = template.New("markdown").Parse(tpl.Markdown); err != nil {
+			return errors.Wrapf(err, "parse %s markdown template err", event)
+		}
+	}
+	if tpl.Text != "" {
+		if next.text, err = template.New("text").Parse(tpl.Text); err != nil {
+			return errors.Wrapf(err, "parse %s text template err", event)
+		}
+	}
+
+	if r.templates[event] == nil {
+		r.templates[event] = make(map[string]*compiled)
+	}
+	r.templates[event][locale] = next
+	return nil
+}
+
+// MustRegister 同 Register，模板有误时 panic
+func (r *Registry) MustRegister(event Event, locale string, tpl *Template) {
+	if err := r.Register(event, locale, tpl); err != nil {
+		panic(err)
+	}
+}
+
+// Render 渲染告警，locale 为空时使用默认语言，找不到对应语言时回退到默认语言和 zh-CN
+func (r *Registry) Render(event Event, locale string, format Format, alert *Alert) (subject, body string, err error) {
+	r.mux.RLock()
+	if locale == "" {
+		locale = r.locale
+	}
+	c := r.lookup(event, locale)
+	if c == nil {
+		c = r.lookup(event, r.locale)
+	}
+	if c == nil {
+		c = r.lookup(event, LocaleZhCN)
+	}
+	r.mux.RUnlock()
+
+	if c == nil || c.subject == nil {
+		return "", "", errors.Errorf("template for event `%s` not found", event)
+	}
+
+	buf := new(bytes.Buffer)
+	if err = c.subject.Execute(buf, alert); err != nil {
+		return "", "", errors.Wrapf(err, "execute %s subject template err", event)
+	}
+	subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	switch {
+	case format == FormatMarkdown && c.markdown != nil:
+		err = c.markdown.Execute(buf, alert)
+	case format == FormatText && c.text != nil:
+		err = c.text.Execute(buf, alert)
+	case format == FormatText && c.markdown != nil:
+		// 未注册纯文本模板时使用 markdown，比 HTML 更可读
+		err = c.markdown.Execute(buf, alert)
+	case c.html != nil:
+		err = c.html.Execute(buf, alert)
+	default:
+		err = errors.Errorf("%s template for event `%s` not found", format, event)
+	}
+	if err != nil {
+		return "", "", errors.Wrapf(err, "execute %s body template err", event)
+	}
+
+	return subject, buf.String(), nil
+}
+
+func (r *Registry) lookup(event Event, locale string) *compiled {
+	if locales, ok := r.templates[event]; ok {
+		return locales[locale]
+	}
+	return nil
+}
+
+// FormatOf 返回 n 期望的正文格式，未声明时为 HTML
+func FormatOf(n Notifier) Format {
+	if f, ok := n.(interface{ Format() Format }); ok {
+		return f.Format()
+	}
+	return FormatHTML
+}
@@ -0,0 +1,13 @@
+package notifier
+
+// DefaultSlackTemplate Slack incoming webhook 的默认请求体
+const DefaultSlackTemplate = `{"text":{{json (printf "*%s*\n%s" .Subject .Body)}}}`
+
+// NewSlack Slack 兼容的 incoming webhook(Mattermost、Rocket.Chat 等同样适用)
+func NewSlack(url string, opts ...Option) (Notifier, error) {
+	w, _, err := newWebhook(url, DefaultSlackTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
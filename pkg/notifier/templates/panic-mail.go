@@ -0,0 +1,146 @@
+package templates
+
+const PanicMail = `
+<!DOCTYPE html>
+<html>
+
+<head>
+    <meta name="viewport" content="width=device-width, initial-scale=1.0"/>
+    <meta http-equiv="Content-Type" content="text/html; charset=UTF-8"/>
+
+    <style type="text/css" rel="stylesheet" media="all">
+        /* Media Queries */
+        @media only screen and (max-width: 500px) {
+            .button {
+                width: 100% !important;
+            }
+        }
+    </style>
+</head>
+
+
+<body style="margin: 0; padding: 0; width: 100%; background-color: #F2F4F6;">
+<table width="100%" cellpadding="0" cellspacing="0">
+    <tr>
+        <td style="width: 100%; margin: 0; padding: 0; background-color: #F2F4F6;" align="center">
+            <table width="100%" cellpadding="0" cellspacing="0">
+                <!-- Logo -->
+                <tr>
+                    <td style="padding: 25px 0; text-align: center;">
+                        系统异常
+                    </td>
+                </tr>
+
+                <!-- Email Body -->
+                <tr>
+                    <td style="width: 100%; margin: 0; padding: 0; border-top: 1px solid #EDEFF2; border-bottom: 1px solid #EDEFF2; background-color: #FFF;"
+                        width="100%">
+                        <table style="width: auto; max-width: 750px; margin: 0 auto; padding: 0;" align="center"
+                               width="750" cellpadding="0" cellspacing="0">
+                            <tr>
+                                <td style="font-family: Arial, 'Helvetica Neue', Helvetica, sans-serif; padding: 35px;">
+                                    <!-- Greeting -->
+                                    <h1 style="margin-top: 0; color: #2F3133; font-size: 19px; font-weight: bold; text-align: left;">
+                                        Hello!
+                                    </h1>
+
+                                    <!-- Intro -->
+                                    <p style="margin-top: 0; color: #74787E; line-height: 1.5em;">
+                                        您收到此电子邮件，请紧急安排处理。
+                                    </p>
+
+                                    <!-- Action Button -->
+                                    <table style="width: 100%; margin: 30px auto; padding: 0;"
+                                           width="100%" cellpadding="0" cellspacing="0">
+                                        <tr style="margin-top: 0; color: #74787E; line-height: 1.5em;">
+                                            <td style="width: 10%;">
+                                                ID:
+                                            </td>
+                                            <td style="width: 90%">
+                                                {{.TraceID}}
+                                            </td>
+                                        </tr>
+
+                                        <tr style="margin-top: 0; color: #74787E; font-size: 16px; line-height: 1.5em;">
+                                            <td style="width: 10%;">
+                                                URL:
+                                            </td>
+                                            <td style="width: 90%">
+                                                {{.Method}} {{.Host}}{{.URL}}
+                                            </td>
+                                        </tr>
+
+                                        <tr style="margin-top: 0; color: #74787E; font-size: 16px; line-height: 1.5em;">
+                                            <td style="width: 10%;">
+                                                Route:
+                                            </td>
+                                            <td style="width: 90%">
+                                                {{.Route}}
+                                            </td>
+                                        </tr>
+{{if .UserID}}
+                                        <tr style="margin-top: 0; color: #74787E; font-size: 16px; line-height: 1.5em;">
+                                            <td style="width: 10%;">
+                                                User:
+                                            </td>
+                                            <td style="width: 90%">
+                                                {{.UserID}} {{.UserName}}
+                                            </td>
+                                        </tr>
+{{end}}
+                                        <tr style="margin-top: 0; color: #74787E; font-size: 16px; line-height: 1.5em;">
+                                            <td style="width: 10%;">
+                                                Header:
+                                            </td>
+                                            <td style="width: 90%">
+                                                {{range $k, $v := .Header}}{{$k}}: {{$v}}<br/>{{end}}
+                                            </td>
+                                        </tr>
+
+                                        <tr style="margin-top: 0; color: #74787E; font-size: 16px; line-height: 1.5em;">
+                                            <td style="width: 10%;">
+                                                Error:
+                                            </td>
+                                            <td style="width: 90%">
+                                                {{.Error}}
+                                            </td>
+                                        </tr>
+
+                                        <tr style="margin-top: 0; color: #74787E; font-size: 16px; line-height: 1.5em;">
+                                            <td style="width: 10%;">
+                                                Stack:
+                                            </td>
+                                            <td style="width: 90%;">
+                                                {{.Stack}}
+                                            </td>
+                                        </tr>
+                                    </table>
+                                </td>
+                            </tr>
+                        </table>
+                    </td>
+                </tr>
+
+                <!-- Footer -->
+                <tr>
+                    <td>
+                        <table style="width: auto; max-width: 570px; margin: 0 auto; padding: 0; text-align: center;"
+                               align="center" width="750" cellpadding="0" cellspacing="0">
+                            <tr>
+                                <td style="font-family: Arial, 'Helvetica Neue', Helvetica, sans-serif; color: #AEAEAE; padding: 35px; text-align: center;">
+                                    <p style="margin-top: 0; color: #74787E; font-size: 12px; line-height: 1.5em;">
+                                        &copy; {{.Year}}
+                                        All rights reserved.
+                                    </p>
+                                </td>
+                            </tr>
+                        </table>
+                    </td>
+                </tr>
+            </table>
+        </td>
+    </tr>
+</table>
+</body>
+</html>
+`
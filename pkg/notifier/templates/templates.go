@@ -0,0 +1,182 @@
+package templates
+
+// 主题模板(text/template)
+const (
+	PanicSubject             = `[系统异常]-{{.URL}}`
+	PanicSubjectEN           = `[Panic]-{{.URL}}`
+	OutboundFailureSubject   = `{{if .Title}}{{.Title}}{{else}}[调用失败]-{{.Method}} {{.URL}}{{end}}`
+	OutboundFailureSubjectEN = `{{if .Title}}{{.Title}}{{else}}[Call Failed]-{{.Method}} {{.URL}}{{end}}`
+	CircuitOpenSubject       = `[熔断开启]-{{.Title}}`
+	CircuitOpenSubjectEN     = `[Circuit Open]-{{.Title}}`
+)
+
+// PanicMailEN 英文 panic 邮件
+const PanicMailEN = `
+<html>
+<body style="font-family: Arial, 'Helvetica Neue', Helvetica, sans-serif; color: #74787E;">
+<h3 style="color: #2F3133;">Panic recovered, please handle it as soon as possible.</h3>
+<table cellpadding="4" cellspacing="0">
+    <tr><td>ID:</td><td>{{.TraceID}}</td></tr>
+    <tr><td>URL:</td><td>{{.Method}} {{.Host}}{{.URL}}</td></tr>
+    <tr><td>Route:</td><td>{{.Route}}</td></tr>
+    {{if .UserID}}<tr><td>User:</td><td>{{.UserID}} {{.UserName}}</td></tr>{{end}}
+    <tr><td>Header:</td><td>{{range $k, $v := .Header}}{{$k}}: {{$v}}<br/>{{end}}</td></tr>
+    <tr><td>Error:</td><td>{{.Error}}</td></tr>
+    <tr><td>Stack:</td><td><pre>{{.Stack}}</pre></td></tr>
+</table>
+<p style="font-size: 12px;">&copy; {{.Year}} All rights reserved.</p>
+</body>
+</html>
+`
+
+// PanicMarkdown 聊天机器人使用的 panic 消息
+const PanicMarkdown = `**请求**: {{.Method}} {{.Host}}{{.URL}}
+**路由**: {{.Route}}
+**Trace ID**: {{.TraceID}}
+{{if .UserID}}**用户**: {{.UserID}} {{.UserName}}
+{{end}}**错误**: {{.Error}}
+
+` + "```" + `
+{{.Stack}}
+` + "```"
+
+const PanicMarkdownEN = `**Request**: {{.Method}} {{.Host}}{{.URL}}
+**Route**: {{.Route}}
+**Trace ID**: {{.TraceID}}
+{{if .UserID}}**User**: {{.UserID}} {{.UserName}}
+{{end}}**Error**: {{.Error}}
+
+` + "```" + `
+{{.Stack}}
+` + "```"
+
+// OutboundFailureMail 调用第三方接口失败的邮件
+const OutboundFailureMail = `
+<html>
+<body style="font-family: Arial, 'Helvetica Neue', Helvetica, sans-serif; color: #74787E;">
+<h3 style="color: #2F3133;">调用第三方接口失败</h3>
+<table cellpadding="4" cellspacing="0">
+    <tr><td>ID:</td><td>{{.TraceID}}</td></tr>
+    <tr><td>请求:</td><td>{{.Method}} {{.URL}}</td></tr>
+    <tr><td>状态码:</td><td>{{.HTTPCode}}</td></tr>
+    <tr><td>响应:</td><td><pre>{{.Body}}</pre></td></tr>
+    <tr><td>错误:</td><td><pre>{{.Error}}</pre></td></tr>
+</table>
+</body>
+</html>
+`
+
+const OutboundFailureMailEN = `
+<html>
+<body style="font-family: Arial, 'Helvetica Neue', Helvetica, sans-serif; color: #74787E;">
+<h3 style="color: #2F3133;">Outbound call failed</h3>
+<table cellpadding="4" cellspacing="0">
+    <tr><td>ID:</td><td>{{.TraceID}}</td></tr>
+    <tr><td>Request:</td><td>{{.Method}} {{.URL}}</td></tr>
+    <tr><td>HTTP Code:</td><td>{{.HTTPCode}}</td></tr>
+    <tr><td>Response:</td><td><pre>{{.Body}}</pre></td></tr>
+    <tr><td>Error:</td><td><pre>{{.Error}}</pre></td></tr>
+</table>
+</body>
+</html>
+`
+
+const OutboundFailureMarkdown = `**请求**: {{.Method}} {{.URL}}
+**Trace ID**: {{.TraceID}}
+**状态码**: {{.HTTPCode}}
+**错误**: {{.Error}}
+
+` + "```" + `
+{{.Body}}
+` + "```"
+
+const OutboundFailureMarkdownEN = `**Request**: {{.Method}} {{.URL}}
+**Trace ID**: {{.TraceID}}
+**HTTP Code**: {{.HTTPCode}}
+**Error**: {{.Error}}
+
+` + "```" + `
+{{.Body}}
+` + "```"
+
+// CircuitOpenMail 熔断器开启的邮件
+const CircuitOpenMail = `
+<html>
+<body style="font-family: Arial, 'Helvetica Neue', Helvetica, sans-serif; color: #74787E;">
+<h3 style="color: #2F3133;">熔断器已开启: {{.Title}}</h3>
+<table cellpadding="4" cellspacing="0">
+    <tr><td>目标:</td><td>{{.Method}} {{.URL}}</td></tr>
+    <tr><td>原因:</td><td>{{.Error}}</td></tr>
+    {{range $k, $v := .Fields}}<tr><td>{{$k}}:</td><td>{{$v}}</td></tr>{{end}}
+</table>
+</body>
+</html>
+`
+
+const CircuitOpenMailEN = `
+<html>
+<body style="font-family: Arial, 'Helvetica Neue', Helvetica, sans-serif; color: #74787E;">
+<h3 style="color: #2F3133;">Circuit opened: {{.Title}}</h3>
+<table cellpadding="4" cellspacing="0">
+    <tr><td>Target:</td><td>{{.Method}} {{.URL}}</td></tr>
+    <tr><td>Reason:</td><td>{{.Error}}</td></tr>
+    {{range $k, $v := .Fields}}<tr><td>{{$k}}:</td><td>{{$v}}</td></tr>{{end}}
+</table>
+</body>
+</html>
+`
+
+const CircuitOpenMarkdown = `**熔断器已开启**: {{.Title}}
+**目标**: {{.Method}} {{.URL}}
+**原因**: {{.Error}}
+{{range $k, $v := .Fields}}**{{$k}}**: {{$v}}
+{{end}}`
+
+const CircuitOpenMarkdownEN = `**Circuit opened**: {{.Title}}
+**Target**: {{.Method}} {{.URL}}
+**Reason**: {{.Error}}
+{{range $k, $v := .Fields}}**{{$k}}**: {{$v}}
+{{end}}`
+
+// PanicText 通用 webhook 使用的纯文本 panic 消息
+const PanicText = `请求: {{.Method}} {{.Host}}{{.URL}}
+路由: {{.Route}}
+Trace ID: {{.TraceID}}
+{{if .UserID}}用户: {{.UserID}} {{.UserName}}
+{{end}}错误: {{.Error}}
+
+{{.Stack}}`
+
+const PanicTextEN = `Request: {{.Method}} {{.Host}}{{.URL}}
+Route: {{.Route}}
+Trace ID: {{.TraceID}}
+{{if .UserID}}User: {{.UserID}} {{.UserName}}
+{{end}}Error: {{.Error}}
+
+{{.Stack}}`
+
+const OutboundFailureText = `请求: {{.Method}} {{.URL}}
+Trace ID: {{.TraceID}}
+状态码: {{.HTTPCode}}
+错误: {{.Error}}
+
+{{.Body}}`
+
+const OutboundFailureTextEN = `Request: {{.Method}} {{.URL}}
+Trace ID: {{.TraceID}}
+HTTP Code: {{.HTTPCode}}
+Error: {{.Error}}
+
+{{.Body}}`
+
+const CircuitOpenText = `熔断器已开启: {{.Title}}
+目标: {{.Method}} {{.URL}}
+原因: {{.Error}}
+{{range $k, $v := .Fields}}{{$k}}: {{$v}}
+{{end}}`
+
+const CircuitOpenTextEN = `Circuit opened: {{.Title}}
+Target: {{.Method}} {{.URL}}
+Reason: {{.Error}}
+{{range $k, $v := .Fields}}{{$k}}: {{$v}}
+{{end}}`
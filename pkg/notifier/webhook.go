@@ -0,0 +1,14 @@
+package notifier
+
+// DefaultWebhookTemplate 通用 webhook 的默认请求体
+const DefaultWebhookTemplate = `{"subject":{{json .Subject}},"body":{{json .Body}}}`
+
+// NewWebhook 通用 JSON webhook，正文为纯文本，任何 2xx 响应都视为发送成功
+func NewWebhook(url string, opts ...Option) (Notifier, error) {
+	w, _, err := newWebhook(url, DefaultWebhookTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+	w.format = FormatText
+	return w, nil
+}
@@ -0,0 +1,15 @@
+package notifier
+
+// DefaultWeComTemplate 企业微信群机器人的默认请求体(markdown 消息)
+const DefaultWeComTemplate = `{"msgtype":"markdown","markdown":{"content":{{json (printf "### %s\n%s" .Subject .Body)}}}}`
+
+// NewWeCom 企业微信群机器人，url 为包含 key 的完整 webhook 地址
+func NewWeCom(url string, opts ...Option) (Notifier, error) {
+	w, _, err := newWebhook(url, DefaultWeComTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	w.check = checkRobotReply
+	return w, nil
+}
@@ -0,0 +1,45 @@
+package signature
+
+import "sync"
+
+var _ KeyStore = (*KeyRing)(nil)
+
+// KeyStore 根据 key id 查找签名密钥
+type KeyStore interface {
+	Secret(keyID string) (secret []byte, ok bool)
+}
+
+// KeyRing 并发安全的内存密钥环；轮换密钥时先 Set 新密钥，待调用方切换完成后再 Remove 旧密钥。
+type KeyRing struct {
+	mux  sync.RWMutex
+	keys map[string][]byte
+}
+
+func NewKeyRing() *KeyRing {
+	return &KeyRing{
+		keys: make(map[string][]byte),
+	}
+}
+
+// Set 添加或替换密钥
+func (k *KeyRing) Set(keyID string, secret []byte) *KeyRing {
+	k.mux.Lock()
+	k.keys[keyID] = secret
+	k.mux.Unlock()
+	return k
+}
+
+// Remove 移除密钥，之后使用该密钥的签名都将校验失败
+func (k *KeyRing) Remove(keyID string) {
+	k.mux.Lock()
+	delete(k.keys, keyID)
+	k.mux.Unlock()
+}
+
+func (k *KeyRing) Secret(keyID string) ([]byte, bool) {
+	k.mux.RLock()
+	defer k.mux.RUnlock()
+
+	secret, ok := k.keys[keyID]
+	return secret, ok
+}
@@ -0,0 +1,53 @@
+package signature
+
+import (
+	"sync"
+	"time"
+)
+
+var _ NonceStore = (*memoryNonceStore)(nil)
+
+// NonceStore 记录已使用过的 nonce，多实例部署时可替换为共享存储(如 redis SETNX)
+type NonceStore interface {
+	// Add 记录 nonce 并保留 ttl 时长；nonce 已存在时返回 false
+	Add(nonce string, ttl time.Duration) (added bool, err error)
+}
+
+type memoryNonceStore struct {
+	mux     sync.Mutex
+	nonces  map[string]time.Time
+	lastGC  time.Time
+	gcEvery time.Duration
+}
+
+// NewMemoryNonceStore 进程内的 NonceStore，过期的 nonce 会在写入时顺带清理
+func NewMemoryNonceStore() NonceStore {
+	return &memoryNonceStore{
+		nonces:  make(map[string]time.Time),
+		lastGC:  time.Now(),
+		gcEvery: time.Minute,
+	}
+}
+
+func (m *memoryNonceStore) Add(nonce string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if now.Sub(m.lastGC) >= m.gcEvery {
+		for k, expire := range m.nonces {
+			if now.After(expire) {
+				delete(m.nonces, k)
+			}
+		}
+		m.lastGC = now
+	}
+
+	if expire, ok := m.nonces[nonce]; ok && now.Before(expire) {
+		return false, nil
+	}
+
+	m.nonces[nonce] = now.Add(ttl)
+	return true, nil
+}
@@ -0,0 +1,104 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// HeaderKeyID 签名所用密钥的标识
+	HeaderKeyID = "X-Signature-Key"
+	// HeaderTimestamp 签名时间(unix 秒)
+	HeaderTimestamp = "X-Signature-Timestamp"
+	// HeaderNonce 一次性随机串，用于防重放
+	HeaderNonce = "X-Signature-Nonce"
+	// HeaderSignature hex 编码的 HMAC-SHA256 签名
+	HeaderSignature = "X-Signature"
+)
+
+var (
+	ErrMissingSignature = errors.New("signature headers required")
+	ErrUnknownKey       = errors.New("signature key not found")
+	ErrTimestamp        = errors.New("signature timestamp out of range")
+	ErrReplayed         = errors.New("signature nonce already used")
+	ErrInvalidSignature = errors.New("signature mismatch")
+)
+
+// Canonical 待签名串: method \n path \n timestamp \n nonce \n hex(sha256(body))
+func Canonical(method, path, timestamp, nonce string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		timestamp,
+		nonce,
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+}
+
+// Sum 使用 secret 计算 canonical 的签名
+func Sum(secret []byte, canonical string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Signer 请求签名方，持有当前使用的密钥
+type Signer struct {
+	keyID  string
+	secret []byte
+	now    func() time.Time
+}
+
+func NewSigner(keyID string, secret []byte) *Signer {
+	return &Signer{
+		keyID:  keyID,
+		secret: secret,
+		now:    time.Now,
+	}
+}
+
+// Sign 计算签名并返回需要附加到请求上的 header，每次调用都会生成新的 nonce，读取随机数失败时返回错误
+func (s *Signer) Sign(method, path string, body []byte) (http.Header, error) {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	header := make(http.Header, 4)
+	header.Set(HeaderKeyID, s.keyID)
+	header.Set(HeaderTimestamp, timestamp)
+	header.Set(HeaderNonce, nonce)
+	header.Set(HeaderSignature, Sum(s.secret, Canonical(method, path, timestamp, nonce, body)))
+	return header, nil
+}
+
+// SignRequest 对 req 签名，body 需与 req 实际发送的内容一致
+func (s *Signer) SignRequest(req *http.Request, body []byte) error {
+	header, err := s.Sign(req.Method, req.URL.RequestURI(), body)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	return nil
+}
+
+func newNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", errors.Wrap(err, "generate signature nonce")
+	}
+	return hex.EncodeToString(buf), nil
+}
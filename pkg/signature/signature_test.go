@@ -0,0 +1,60 @@
+package signature
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	keys := NewKeyRing().Set("k1", []byte("secret"))
+	signer := NewSigner("k1", []byte("secret"))
+	verifier := NewVerifier(keys)
+
+	body := []byte(`{"name":"juice"}`)
+	header := mustSign(t, signer, http.MethodPost, "/demo?a=1", body)
+
+	keyID, err := verifier.Verify(http.MethodPost, "/demo?a=1", header, body)
+	if err != nil || keyID != "k1" {
+		t.Fatalf("verify got key %q err %v", keyID, err)
+	}
+
+	if _, err := verifier.Verify(http.MethodPost, "/demo?a=1", header, body); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("replay got err %v", err)
+	}
+
+	header = mustSign(t, signer, http.MethodPost, "/demo?a=1", body)
+	if _, err := verifier.Verify(http.MethodPost, "/demo?a=1", header, []byte("{}")); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("tampered body got err %v", err)
+	}
+}
+
+func TestVerifySkewAndRotation(t *testing.T) {
+	keys := NewKeyRing().Set("old", []byte("s1")).Set("new", []byte("s2"))
+	verifier := NewVerifier(keys, WithSkew(time.Second*30))
+
+	stale := NewSigner("new", []byte("s2"))
+	stale.now = func() time.Time { return time.Now().Add(-time.Minute) }
+	if _, err := verifier.Verify(http.MethodGet, "/", mustSign(t, stale, http.MethodGet, "/", nil), nil); !errors.Is(err, ErrTimestamp) {
+		t.Fatalf("stale timestamp got err %v", err)
+	}
+
+	old := NewSigner("old", []byte("s1"))
+	if _, err := verifier.Verify(http.MethodGet, "/", mustSign(t, old, http.MethodGet, "/", nil), nil); err != nil {
+		t.Fatalf("old key before removal got err %v", err)
+	}
+
+	keys.Remove("old")
+	if _, err := verifier.Verify(http.MethodGet, "/", mustSign(t, old, http.MethodGet, "/", nil), nil); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("old key after removal got err %v", err)
+	}
+}
+
+func mustSign(t *testing.T, s *Signer, method, path string, body []byte) http.Header {
+	header, err := s.Sign(method, path, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return header
+}
@@ -0,0 +1,102 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultSkew 允许的客户端与服务端时钟偏差
+	DefaultSkew = time.Minute * 5
+)
+
+type VerifierOption func(*Verifier)
+
+// WithSkew 设置允许的时钟偏差
+func WithSkew(skew time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.skew = skew
+	}
+}
+
+// WithNonceStore 设置 nonce 存储，nil 表示关闭防重放校验
+func WithNonceStore(store NonceStore) VerifierOption {
+	return func(v *Verifier) {
+		v.nonces = store
+	}
+}
+
+// Verifier 请求验签方
+type Verifier struct {
+	keys   KeyStore
+	skew   time.Duration
+	nonces NonceStore
+	now    func() time.Time
+}
+
+func NewVerifier(keys KeyStore, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		keys:   keys,
+		skew:   DefaultSkew,
+		nonces: NewMemoryNonceStore(),
+		now:    time.Now,
+	}
+
+	for _, o := range opts {
+		o(v)
+	}
+
+	return v
+}
+
+// Verify 校验签名，成功时返回签名所用的 key id
+func (v *Verifier) Verify(method, path string, header http.Header, body []byte) (keyID string, err error) {
+	keyID = header.Get(HeaderKeyID)
+	timestamp := header.Get(HeaderTimestamp)
+	nonce := header.Get(HeaderNonce)
+	signature := header.Get(HeaderSignature)
+	if keyID == "" || timestamp == "" || nonce == "" || signature == "" {
+		return "", ErrMissingSignature
+	}
+
+	secret, ok := v.keys.Secret(keyID)
+	if !ok {
+		return "", errors.Wrapf(ErrUnknownKey, "key id `%s`", keyID)
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", errors.Wrapf(ErrTimestamp, "parse timestamp `%s`", timestamp)
+	}
+
+	if diff := v.now().Sub(time.Unix(ts, 0)); diff > v.skew || diff < -v.skew {
+		return "", errors.Wrapf(ErrTimestamp, "skew %s", diff)
+	}
+
+	expected := Sum(secret, Canonical(method, path, timestamp, nonce, body))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return "", ErrInvalidSignature
+	}
+
+	if v.nonces != nil {
+		// 时间窗口为 [-skew, +skew]，nonce 至少需要保留 2*skew
+		added, err := v.nonces.Add(keyID+":"+nonce, v.skew*2)
+		if err != nil {
+			return "", errors.Wrap(err, "nonce store")
+		}
+		if !added {
+			return "", ErrReplayed
+		}
+	}
+
+	return keyID, nil
+}
+
+// VerifyRequest 校验 req 的签名，body 为已读取的请求体
+func (v *Verifier) VerifyRequest(req *http.Request, body []byte) (string, error) {
+	return v.Verify(req.Method, req.URL.RequestURI(), req.Header, body)
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mel2oo/juice/transport/http"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gopkg.in/yaml.v2"
+)
+
+type codecItem struct {
+	XMLName xml.Name `json:"-" yaml:"-" xml:"item"`
+	Name    string   `json:"name" yaml:"name" xml:"name" binding:"required"`
+	Count   int      `json:"count" yaml:"count" xml:"count"`
+}
+
+func TestCodecNegotiation(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := mux.Group("")
+	g.POST("/items", func(ctx http.Context) {
+		item := new(codecItem)
+		if err := ctx.ShouldBind(item); err != nil {
+			ctx.AbortWithError(http.NewError(nethttp.StatusBadRequest, http.ParamBindError, http.Text(http.ParamBindError)).WithErr(err))
+			return
+		}
+		item.Count++
+		ctx.Payload(item)
+	})
+	g.POST("/echo", func(ctx http.Context) {
+		msg := new(wrapperspb.StringValue)
+		if err := ctx.ShouldBind(msg); err != nil {
+			ctx.AbortWithError(http.NewError(nethttp.StatusBadRequest, http.ParamBindError, http.Text(http.ParamBindError)).WithErr(err))
+			return
+		}
+		ctx.Payload(wrapperspb.String(strings.ToUpper(msg.Value)))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	do := func(path, contentType, accept string, body []byte) (string, []byte) {
+		req, _ := nethttp.NewRequest(nethttp.MethodPost, srv.URL+path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		raw, _ := ioutil.ReadAll(resp.Body)
+		return resp.Header.Get("Content-Type"), raw
+	}
+
+	// YAML 请求，XML 响应
+	ct, raw := do("/items", "application/x-yaml", "text/html;q=0.9, application/xml", []byte("name: juice\ncount: 1\n"))
+	item := new(codecItem)
+	if !strings.HasPrefix(ct, http.MIMEXML) || xml.Unmarshal(raw, item) != nil || item.Name != "juice" || item.Count != 2 {
+		t.Fatalf("unexpected xml response %s %s", ct, raw)
+	}
+
+	// 浏览器的 Accept 虽然列出了 application/xml，*/* 仍可接受 JSON，响应 JSON
+	browser := "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8"
+	ct, raw = do("/items", http.MIMEJSON, browser, []byte(`{"name":"juice","count":1}`))
+	item = new(codecItem)
+	if !strings.HasPrefix(ct, http.MIMEJSON) || json.Unmarshal(raw, item) != nil || item.Count != 2 {
+		t.Fatalf("unexpected browser response %s %s", ct, raw)
+	}
+
+	// 排除 JSON 时，具体的 XML 优先于 */*
+	ct, _ = do("/items", http.MIMEJSON, "application/json;q=0, application/xml;q=0.5, */*", []byte(`{"name":"juice"}`))
+	if !strings.HasPrefix(ct, http.MIMEXML) {
+		t.Fatalf("expected xml when json is excluded, got %s", ct)
+	}
+
+	// MessagePack 请求，YAML 响应
+	var body []byte
+	codec.NewEncoderBytes(&body, new(codec.MsgpackHandle)).Encode(map[string]interface{}{"name": "juice", "count": 2})
+	ct, raw = do("/items", http.MIMEMsgPack, "application/yaml", body)
+	item = new(codecItem)
+	if !strings.HasPrefix(ct, http.MIMEYAML) || yaml.Unmarshal(raw, item) != nil || item.Count != 3 {
+		t.Fatalf("unexpected yaml response %s %s", ct, raw)
+	}
+
+	// 非 proto.Message 的 Failure 退回 JSON
+	ct, raw = do("/items", http.MIMEJSON, http.MIMEProtobuf, []byte(`{}`))
+	failure := new(http.Failure)
+	if !strings.HasPrefix(ct, http.MIMEJSON) || json.Unmarshal(raw, failure) != nil || failure.Code != http.ParamBindError {
+		t.Fatalf("unexpected failure %s %s", ct, raw)
+	}
+
+	// protobuf 请求与响应
+	body, _ = proto.Marshal(wrapperspb.String("juice"))
+	ct, raw = do("/echo", http.MIMEProtobuf, http.MIMEProtobuf, body)
+	msg := new(wrapperspb.StringValue)
+	if ct != http.MIMEProtobuf || proto.Unmarshal(raw, msg) != nil || msg.Value != "JUICE" {
+		t.Fatalf("unexpected protobuf response %s %q", ct, raw)
+	}
+
+	// 按 q 值排序，protobuf 优先于 q 更低的 JSON 与通配符
+	for _, accept := range []string{
+		"application/x-protobuf, application/json;q=0.5",
+		"application/x-protobuf, */*;q=0.1",
+	} {
+		ct, raw = do("/echo", http.MIMEProtobuf, accept, body)
+		msg = new(wrapperspb.StringValue)
+		if ct != http.MIMEProtobuf || proto.Unmarshal(raw, msg) != nil || msg.Value != "JUICE" {
+			t.Fatalf("accept %q: unexpected response %s %q", accept, ct, raw)
+		}
+	}
+
+	// q 相同时优先 JSON
+	ct, _ = do("/echo", http.MIMEProtobuf, "application/x-protobuf, application/json", body)
+	if !strings.HasPrefix(ct, http.MIMEJSON) {
+		t.Fatalf("expected json on tie, got %s", ct)
+	}
+}
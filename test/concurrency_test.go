@@ -0,0 +1,261 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/errors"
+	"github.com/mel2oo/juice/pkg/limiter"
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/pkg/logger/observer"
+	"github.com/mel2oo/juice/transport/grpc"
+	"github.com/mel2oo/juice/transport/http"
+	"github.com/prometheus/client_golang/prometheus"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestHTTPConcurrencyLimiter(t *testing.T) {
+	l := limiter.New(limiter.Fixed(2))
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger(),
+		http.WithConcurrencyLimit(http.ConcurrencyConfig{Limiter: l}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// entered 带缓冲，未被拒绝的请求不会阻塞在 handler 中导致测试挂起
+	entered, release := make(chan struct{}, 2), make(chan struct{})
+	mux.Group("").GET("/slow", func(ctx http.Context) {
+		entered <- struct{}{}
+		<-release
+		ctx.Payload("done")
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	var once sync.Once
+	defer once.Do(func() { close(release) })
+
+	// normal 可使用 80% 的上限，即 1 个并发
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if resp, err := srv.Client().Get(srv.URL + "/slow"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-entered
+
+	resp, err := srv.Client().Get(srv.URL + "/slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	failure := new(http.Failure)
+	json.NewDecoder(resp.Body).Decode(failure)
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusServiceUnavailable || failure.Code != http.ServiceBusy || resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("unexpected response %d %+v", resp.StatusCode, failure)
+	}
+
+	// 健康检查为 critical，过载时仍然响应
+	if resp, err = srv.Client().Get(srv.URL + "/system/health"); err != nil || resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("health check should not be shed: %v %v", err, resp)
+	}
+	resp.Body.Close()
+
+	once.Do(func() { close(release) })
+	<-done
+	if s := l.Stats(); s.Inflight != 0 || s.Shed[limiter.PriorityNormal] != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+}
+
+type blockingHealth struct {
+	grpc_health_v1.UnimplementedHealthServer
+	entered, release chan struct{}
+}
+
+func (h blockingHealth) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	h.entered <- struct{}{}
+	<-h.release
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func TestGRPCConcurrencyLimiter(t *testing.T) {
+	l := limiter.New(limiter.Fixed(1))
+	log, _ := observer.New(logger.DebugLevel)
+	// 将健康检查视为普通请求以便验证拒绝
+	srv := grpc.NewServer(grpc.Logger(log), grpc.ConcurrencyLimiter(l, func(context.Context, string) limiter.Priority {
+		return limiter.PriorityNormal
+	}))
+	health := blockingHealth{entered: make(chan struct{}, 2), release: make(chan struct{})}
+	grpc_health_v1.RegisterHealthServer(srv.Server, health)
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	defer srv.Stop()
+	var once sync.Once
+	defer once.Do(func() { close(health.release) })
+
+	conn, err := ggrpc.Dial("bufnet",
+		ggrpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		ggrpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Check(context.Background(), new(grpc_health_v1.HealthCheckRequest))
+		done <- err
+	}()
+	<-health.entered
+
+	_, err = client.Check(context.Background(), new(grpc_health_v1.HealthCheckRequest))
+	if e, ok := errors.FromError(err); status.Code(err) != codes.ResourceExhausted || !ok || e.Code != limiter.ServiceBusy {
+		t.Fatalf("expected ServiceBusy, got %v", err)
+	}
+
+	once.Do(func() { close(health.release) })
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// recordingAlgorithm 固定上限，记录每次 Update 是否视为过载
+type recordingAlgorithm struct {
+	mu      sync.Mutex
+	dropped []bool
+}
+
+func (a *recordingAlgorithm) Limit() int { return 10 }
+
+func (a *recordingAlgorithm) Update(rtt time.Duration, inflight int, dropped bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.dropped = append(a.dropped, dropped)
+}
+
+func (a *recordingAlgorithm) take() []bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	d := a.dropped
+	a.dropped = nil
+	return d
+}
+
+func TestHTTPConcurrencyOverloadSignals(t *testing.T) {
+	alg := new(recordingAlgorithm)
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger(),
+		http.WithConcurrencyLimit(http.ConcurrencyConfig{Limiter: limiter.New(alg)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := mux.Group("")
+	g.GET("/busy", func(ctx http.Context) {
+		ctx.AbortWithError(http.NewErrorFromCode(http.ServiceBusy))
+	})
+	g.GET("/unavailable", func(ctx http.Context) {
+		ctx.AbortWithError(http.NewError(nethttp.StatusServiceUnavailable, 20503, "maintenance"))
+	})
+	g.GET("/timeout", func(ctx http.Context) {
+		ctx.AbortWithError(http.NewError(nethttp.StatusGatewayTimeout, 20504, "upstream timeout"))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for path, want := range map[string]bool{"/busy": false, "/unavailable": false, "/timeout": true} {
+		resp, err := srv.Client().Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if d := alg.take(); len(d) != 1 || d[0] != want {
+			t.Fatalf("%s: expected dropped=%v, got %v", path, want, d)
+		}
+	}
+}
+
+type erroringHealth struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (erroringHealth) Check(_ context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	switch req.Service {
+	case "busy":
+		return nil, errors.New(limiter.ServiceBusy)
+	case "exhausted":
+		return nil, status.Error(codes.ResourceExhausted, "quota exceeded")
+	case "unavailable":
+		return nil, status.Error(codes.Unavailable, "backend down")
+	}
+	return &grpc_health_v1.HealthCheckResponse{}, nil
+}
+
+func TestGRPCConcurrencyOverloadSignals(t *testing.T) {
+	alg := new(recordingAlgorithm)
+	log, _ := observer.New(logger.DebugLevel)
+	srv := grpc.NewServer(grpc.Logger(log), grpc.ConcurrencyLimiter(limiter.New(alg), nil))
+	grpc_health_v1.RegisterHealthServer(srv.Server, erroringHealth{})
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := ggrpc.Dial("bufnet",
+		ggrpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		ggrpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	for service, want := range map[string]bool{"": false, "busy": false, "exhausted": false, "unavailable": true} {
+		client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+		if d := alg.take(); len(d) != 1 || d[0] != want {
+			t.Fatalf("%q: expected dropped=%v, got %v", service, want, d)
+		}
+	}
+}
+
+// 未指定 Limiter 时创建的默认 Limiter 自动注册指标
+func TestHTTPConcurrencyLimiterMetrics(t *testing.T) {
+	if _, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger(),
+		http.WithConcurrencyLimit(http.ConcurrencyConfig{})); err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() != "switch_juice_limiter_limit" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "limiter" && strings.HasPrefix(label.GetValue(), "http") {
+					return
+				}
+			}
+		}
+	}
+	t.Fatal("default limiter metrics not registered")
+}
@@ -0,0 +1,147 @@
+package test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mel2oo/juice/transport/http"
+)
+
+func TestCors(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger(),
+		http.WithCors(http.CorsConfig{
+			AllowedOrigins: []string{"https://www.example.com", "https://*.example.org"},
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Group("").GET("/public", func(ctx http.Context) {
+		ctx.Payload("public")
+	})
+	api := mux.Group("/api").Cors(http.CorsConfig{
+		AllowedOriginPatterns: []string{`^https://app-\d+\.example\.com$`},
+		AllowedMethods:        []string{nethttp.MethodGet, nethttp.MethodPost},
+		AllowedHeaders:        []string{"Authorization", "Content-Type"},
+		ExposedHeaders:        []string{"X-Trace-Id"},
+		MaxAge:                10 * time.Minute,
+		AllowCredentials:      true,
+	})
+	api.POST("/users", func(ctx http.Context) {
+		ctx.Payload("created")
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	do := func(method, path, origin string, header map[string]string) *nethttp.Response {
+		req, _ := nethttp.NewRequest(method, srv.URL+path, nil)
+		req.Header.Set("Origin", origin)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := do(nethttp.MethodGet, "/public", "https://www.example.com", nil)
+	if resp.StatusCode != nethttp.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != "https://www.example.com" {
+		t.Fatalf("unexpected exact origin response %d %v", resp.StatusCode, resp.Header)
+	}
+	if resp = do(nethttp.MethodGet, "/public", "https://a.b.example.org", nil); resp.Header.Get("Access-Control-Allow-Origin") != "https://a.b.example.org" {
+		t.Fatalf("wildcard origin should be allowed %v", resp.Header)
+	}
+	if resp = do(nethttp.MethodGet, "/public", "https://evil-example.org", nil); resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("unexpected allowed origin %v", resp.Header)
+	}
+
+	preflight := map[string]string{
+		"Access-Control-Request-Method":  nethttp.MethodPost,
+		"Access-Control-Request-Headers": "Authorization",
+	}
+	resp = do(nethttp.MethodOptions, "/api/users", "https://app-12.example.com", preflight)
+	if resp.StatusCode != nethttp.StatusNoContent ||
+		resp.Header.Get("Access-Control-Allow-Origin") != "https://app-12.example.com" ||
+		resp.Header.Get("Access-Control-Allow-Credentials") != "true" ||
+		resp.Header.Get("Access-Control-Max-Age") != "600" {
+		t.Fatalf("unexpected preflight response %d %v", resp.StatusCode, resp.Header)
+	}
+
+	// 路由组的策略替代全局策略
+	if resp = do(nethttp.MethodOptions, "/api/users", "https://www.example.com", preflight); resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("global origin should not be allowed by group policy %v", resp.Header)
+	}
+
+	resp = do(nethttp.MethodPost, "/api/users", "https://app-1.example.com", nil)
+	if resp.StatusCode != nethttp.StatusOK || resp.Header.Get("Access-Control-Expose-Headers") != "X-Trace-Id" {
+		t.Fatalf("unexpected actual response %d %v", resp.StatusCode, resp.Header)
+	}
+}
+
+func TestCorsInvalidConfig(t *testing.T) {
+	_, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger(),
+		http.WithCors(http.CorsConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}))
+	if err == nil {
+		t.Fatal("expected error for wildcard origin with credentials")
+	}
+}
+
+func TestCorsPreflightSkipsParentHandlers(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := func(ctx http.Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			ctx.AbortWithError(http.NewError(nethttp.StatusUnauthorized, 20401, "unauthorized"))
+		}
+	}
+	mux.Group("/api", auth).Cors(http.CorsConfig{
+		AllowedOrigins: []string{"https://www.example.com"},
+		AllowedHeaders: []string{"Authorization"},
+	}).GET("/me", func(ctx http.Context) {
+		ctx.Payload("me")
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	// 预检请求不携带 Authorization，不经过父路由组的鉴权
+	req, _ := nethttp.NewRequest(nethttp.MethodOptions, srv.URL+"/api/me", nil)
+	req.Header.Set("Origin", "https://www.example.com")
+	req.Header.Set("Access-Control-Request-Method", nethttp.MethodGet)
+	req.Header.Set("Access-Control-Request-Headers", "Authorization")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != "https://www.example.com" {
+		t.Fatalf("unexpected preflight response %d %v", resp.StatusCode, resp.Header)
+	}
+
+	// 实际请求仍然需要鉴权
+	req, _ = nethttp.NewRequest(nethttp.MethodGet, srv.URL+"/api/me", nil)
+	req.Header.Set("Origin", "https://www.example.com")
+	if resp, err = srv.Client().Do(req); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", resp.StatusCode)
+	}
+
+	req.Header.Set("Authorization", "token")
+	if resp, err = srv.Client().Do(req); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != "https://www.example.com" {
+		t.Fatalf("unexpected actual response %d %v", resp.StatusCode, resp.Header)
+	}
+}
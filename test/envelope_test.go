@@ -0,0 +1,77 @@
+package test
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mel2oo/juice/transport/http"
+)
+
+type listItemsRequest struct {
+	http.PageRequest
+	Keyword string `form:"keyword"`
+}
+
+func TestEnvelope(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger(), http.WithEnvelope(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := mux.Group("")
+	g.GET("/items", http.Handle(func(ctx http.Context, req *listItemsRequest) (*http.Page, http.Error) {
+		items := []string{"a", "b"}
+		return http.NewPage(items, req.PageRequest, 45), nil
+	}))
+	g.GET("/hello", func(ctx http.Context) {
+		ctx.Payload("hello")
+	})
+	g.GET("/panic", func(ctx http.Context) {
+		panic("boom")
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	get := func(path string) (int, *http.Envelope) {
+		resp, err := srv.Client().Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		env := new(http.Envelope)
+		json.NewDecoder(resp.Body).Decode(env)
+		return resp.StatusCode, env
+	}
+
+	code, env := get("/hello")
+	if code != nethttp.StatusOK || env.Code != 0 || env.Message != "ok" || env.Data != "hello" || env.TraceID == "" {
+		t.Fatalf("unexpected envelope %d %+v", code, env)
+	}
+
+	_, env = get("/items?page=2&page_size=20")
+	if p := env.Pagination; p == nil || p.Page != 2 || p.Total != 45 || p.TotalPages != 3 || !p.HasNext || len(env.Data.([]interface{})) != 2 {
+		t.Fatalf("unexpected page %+v %+v", env, env.Pagination)
+	}
+
+	code, env = get("/items?page_size=5000")
+	if code != nethttp.StatusBadRequest || env.Code != http.ParamBindError || len(env.Details) != 1 || env.Details[0].Field != "page_size" {
+		t.Fatalf("unexpected failure %d %+v", code, env)
+	}
+
+	code, env = get("/panic")
+	if code != nethttp.StatusInternalServerError || env.Code != http.ServerError || env.TraceID == "" {
+		t.Fatalf("unexpected panic envelope %d %+v", code, env)
+	}
+}
+
+func TestPageRequest(t *testing.T) {
+	req := http.PageRequest{Page: 3}
+	if req.Offset() != 40 || req.Limit() != http.DefaultPageSize {
+		t.Fatalf("unexpected offset %d limit %d", req.Offset(), req.Limit())
+	}
+	if p := http.NewPage(nil, http.PageRequest{}, 0).Pagination; p.Page != 1 || p.TotalPages != 0 || p.HasNext {
+		t.Fatalf("unexpected pagination %+v", p)
+	}
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	jerrors "github.com/mel2oo/juice/pkg/errors"
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/pkg/logger/observer"
+	"github.com/mel2oo/juice/transport/grpc"
+	"github.com/mel2oo/juice/transport/http"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const orderNotFound = 30404
+
+func init() {
+	jerrors.MustRegister(jerrors.Code{
+		Code:       orderNotFound,
+		HTTPStatus: nethttp.StatusNotFound,
+		Messages:   map[string]string{"zh": "订单 %s 不存在", "en": "order %s not found"},
+	})
+}
+
+type notFoundHealth struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (notFoundHealth) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return nil, jerrors.New(orderNotFound, "o1")
+}
+
+func TestGRPCErrorTranslation(t *testing.T) {
+	log, _ := observer.New(logger.DebugLevel)
+	srv := grpc.NewServer(grpc.Logger(log))
+	grpc_health_v1.RegisterHealthServer(srv.Server, notFoundHealth{})
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := ggrpc.Dial("bufnet",
+		ggrpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		ggrpc.WithInsecure(),
+		ggrpc.WithUnaryInterceptor(grpc.UnaryClientErrorInterceptor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), grpc.AcceptLanguageMetadataKey, "en-US")
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(ctx, new(grpc_health_v1.HealthCheckRequest))
+
+	e, ok := jerrors.FromError(err)
+	if !ok || e.Code != orderNotFound || e.Message != "order o1 not found" || status.Code(err) != codes.NotFound {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !errors.Is(err, jerrors.New(orderNotFound)) {
+		t.Fatal("expected errors.Is to match business code")
+	}
+
+	// 经 HTTP 返回时使用同一业务码，本地创建的 Error 描述信息按 Accept-Language 本地化
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Group("").GET("/orders", func(ctx http.Context) {
+		ctx.AbortWithError(http.FromError(fmt.Errorf("load order: %w", jerrors.New(orderNotFound, "o1"))))
+	})
+	hs := httptest.NewServer(mux)
+	defer hs.Close()
+
+	req, _ := nethttp.NewRequest(nethttp.MethodGet, hs.URL+"/orders", nil)
+	req.Header.Set("Accept-Language", "zh-CN")
+	resp, err := hs.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	failure := new(http.Failure)
+	json.NewDecoder(resp.Body).Decode(failure)
+	if resp.StatusCode != nethttp.StatusNotFound || failure.Code != orderNotFound || failure.Message != "订单 o1 不存在" {
+		t.Fatalf("unexpected response %d %+v", resp.StatusCode, failure)
+	}
+}
+
+var errOrderLocked = http.NewError(nethttp.StatusConflict, 30409, "订单 %s 已锁定")
+
+func TestHTTPErrorWrapping(t *testing.T) {
+	cause := errors.New("row locked")
+	e := errOrderLocked.WithArgs("o1").WithErr(cause).WithDetails(map[string]string{"order_id": "o1"})
+
+	if errOrderLocked.GetMsg() != "订单 %s 已锁定" || errOrderLocked.GetErr() != nil || len(errOrderLocked.GetDetails()) != 0 {
+		t.Fatal("predefined error must not be mutated")
+	}
+	if e.GetMsg() != "订单 o1 已锁定" || e.WithMsg("locked").GetMsg() != "locked" || len(e.GetDetails()) != 1 {
+		t.Fatalf("unexpected error %v", e)
+	}
+
+	e.GetDetails()[0] = "changed"
+	if d, ok := e.GetDetails()[0].(map[string]string); !ok || d["order_id"] != "o1" {
+		t.Fatalf("GetDetails must return a copy, got %v", e.GetDetails())
+	}
+
+	var err error = fmt.Errorf("service: %w", e)
+	if !errors.Is(err, errOrderLocked) || !errors.Is(err, cause) || errors.Is(err, http.NewError(nethttp.StatusConflict, 30410, "")) {
+		t.Fatal("unexpected errors.Is result")
+	}
+	var he http.Error
+	if !errors.As(err, &he) || he.GetBusinessCode() != 30409 || http.FromError(err) != he {
+		t.Fatalf("unexpected errors.As result %v", he)
+	}
+	if http.FromError(nil) != nil {
+		t.Fatal("FromError(nil) must return nil")
+	}
+
+	// 与 pkg/errors 的业务码互相匹配
+	coded := http.NewErrorFromCode(orderNotFound, "o2")
+	if !errors.Is(coded, jerrors.New(orderNotFound)) || !errors.Is(jerrors.New(orderNotFound), coded) {
+		t.Fatal("expected business codes to match across packages")
+	}
+	if coded.WithArgs("o3").GetMsg() != "订单 o3 不存在" || coded.GetHttpCode() != nethttp.StatusNotFound {
+		t.Fatalf("unexpected coded error %v", coded)
+	}
+
+	// WithDetails 的明细输出到 extra，details 保留给 FieldError
+	mux, merr := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger())
+	if merr != nil {
+		t.Fatal(merr)
+	}
+	mux.Group("").GET("/locked", func(ctx http.Context) {
+		ctx.AbortWithError(e)
+	})
+	hs := httptest.NewServer(mux)
+	defer hs.Close()
+
+	resp, err := hs.Client().Get(hs.URL + "/locked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	failure := new(http.Failure)
+	json.NewDecoder(resp.Body).Decode(failure)
+	if len(failure.Extra) != 1 || len(failure.Details) != 0 {
+		t.Fatalf("unexpected failure %+v", failure)
+	}
+	if d, ok := failure.Extra[0].(map[string]interface{}); !ok || d["order_id"] != "o1" {
+		t.Fatalf("unexpected extra %+v", failure.Extra)
+	}
+}
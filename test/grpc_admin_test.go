@@ -0,0 +1,70 @@
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/pkg/logger/observer"
+	"github.com/mel2oo/juice/transport/grpc"
+
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// adminToken 要求 metadata 中携带 authorization: secret
+func adminToken(ctx context.Context, req interface{}, info *ggrpc.UnaryServerInfo, handler ggrpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("authorization"); len(v) == 0 || v[0] != "secret" {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return handler(ctx, req)
+}
+
+func TestGRPCLoggerAdminAuth(t *testing.T) {
+	log, _ := observer.New(logger.InfoLevel)
+	registry := logger.NewRegistry()
+	srv := grpc.NewServer(grpc.Logger(log), grpc.LoggerAdmin(registry, adminToken))
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := ggrpc.Dial("bufnet",
+		ggrpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		ggrpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	req, _ := structpb.NewStruct(map[string]interface{}{"name": "grpc", "level": "debug"})
+	method := "/" + grpc.LoggerAdminServiceName + "/SetLevel"
+
+	err = conn.Invoke(context.Background(), method, req, new(structpb.Struct))
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+	if log.GetLevel() != logger.InfoLevel {
+		t.Fatalf("level changed without auth: %v", log.GetLevel())
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "secret")
+	if err := conn.Invoke(ctx, method, req, new(structpb.Struct)); err != nil {
+		t.Fatal(err)
+	}
+	if log.GetLevel() != logger.DebugLevel {
+		t.Fatalf("expected debug level, got %v", log.GetLevel())
+	}
+
+	if err := conn.Invoke(context.Background(), "/"+grpc.LoggerAdminServiceName+"/ListLevels", new(emptypb.Empty), new(structpb.Struct)); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/pkg/logger/observer"
+	grpc_logging "github.com/mel2oo/juice/transport/grpc/middleware/logging"
+
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type loggingHealth struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (loggingHealth) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	logger.FromContext(ctx).Infow("checking", "service", req.Service)
+	if req.Service == "broken" {
+		return nil, errors.New("boom")
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func TestGRPCLoggingInterceptor(t *testing.T) {
+	log, logs := observer.New(logger.DebugLevel)
+	srv := ggrpc.NewServer(ggrpc.UnaryInterceptor(grpc_logging.UnaryServerInterceptor(log)))
+	grpc_health_v1.RegisterHealthServer(srv, loggingHealth{})
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := ggrpc.Dial("bufnet",
+		ggrpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		ggrpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		grpc_logging.TraceIDMetadataKey, "trace-1",
+		grpc_logging.UserIDMetadataKey, "42",
+	)
+	if _, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "ok"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// handler 通过 logger.FromContext 取得附带请求字段的 Logger
+	checking := logs.FilterMessage("checking").All()
+	if len(checking) != 1 {
+		t.Fatalf("expected handler log, got %+v", logs.All())
+	}
+	want := map[string]interface{}{
+		"trace_id": "trace-1",
+		"user_id":  int64(42),
+		"route":    "/grpc.health.v1.Health/Check",
+		"method":   "Check",
+		"service":  "ok",
+	}
+	for k, v := range want {
+		if got, _ := checking[0].Field(k); got != v {
+			t.Fatalf("field %s: got %v (%T), want %v", k, got, got, v)
+		}
+	}
+
+	finished := logs.FilterMessage("finished unary call").All()
+	if len(finished) != 1 || finished[0].Level != logger.InfoLevel {
+		t.Fatalf("expected info finish log, got %+v", finished)
+	}
+	if v, _ := finished[0].Field("grpc.code"); v != "OK" {
+		t.Fatalf("unexpected grpc.code %v", v)
+	}
+	if v, _ := finished[0].Field("user_id"); v != int64(42) {
+		t.Fatalf("unexpected user_id %v (%T)", v, v)
+	}
+
+	// 非数字的 user id 记录到 user_id_raw，失败的调用以 error 级别记录
+	logs.TakeAll()
+	ctx = metadata.AppendToOutgoingContext(context.Background(), grpc_logging.UserIDMetadataKey, "alice")
+	if _, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "broken"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	finished = logs.FilterMessage("finished unary call").All()
+	if len(finished) != 1 || finished[0].Level != logger.ErrorLevel {
+		t.Fatalf("expected error finish log, got %+v", finished)
+	}
+	if v, _ := finished[0].Field("grpc.code"); v != "Unknown" {
+		t.Fatalf("unexpected grpc.code %v", v)
+	}
+	if v, _ := finished[0].Field("user_id_raw"); v != "alice" {
+		t.Fatalf("unexpected user_id_raw %v", v)
+	}
+	if _, ok := finished[0].Field("user_id"); ok {
+		t.Fatalf("user_id should be absent: %+v", finished[0].Fields)
+	}
+	if v, _ := finished[0].Field("trace_id"); v == "" || v == nil {
+		t.Fatalf("expected generated trace_id, got %+v", finished[0].Fields)
+	}
+}
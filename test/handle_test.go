@@ -0,0 +1,100 @@
+package test
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mel2oo/juice/transport/http"
+)
+
+type updateItemRequest struct {
+	ID      int64  `uri:"id" binding:"required"`
+	Token   string `header:"X-Token" binding:"required"`
+	DryRun  bool   `form:"dry_run"`
+	Name    string `json:"name" binding:"required"`
+	Comment string `json:"comment"`
+}
+
+type updateItemResponse struct {
+	ID     int64  `json:"id"`
+	Token  string `json:"token"`
+	DryRun bool   `json:"dry_run"`
+	Name   string `json:"name"`
+}
+
+func TestHandle(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Group("/items").PUT("/:id", http.Handle(func(ctx http.Context, req *updateItemRequest) (*updateItemResponse, http.Error) {
+		if req.Name == "forbidden" {
+			return nil, http.NewError(nethttp.StatusForbidden, 20001, "forbidden")
+		}
+		return &updateItemResponse{ID: req.ID, Token: req.Token, DryRun: req.DryRun, Name: req.Name}, nil
+	}))
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	put := func(body string, token string, out interface{}) int {
+		req, _ := nethttp.NewRequest(nethttp.MethodPut, srv.URL+"/items/42?dry_run=true", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("X-Token", token)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		json.NewDecoder(resp.Body).Decode(out)
+		return resp.StatusCode
+	}
+
+	resp := new(updateItemResponse)
+	if code := put(`{"name":"juice"}`, "t1", resp); code != nethttp.StatusOK ||
+		*resp != (updateItemResponse{ID: 42, Token: "t1", DryRun: true, Name: "juice"}) {
+		t.Fatalf("unexpected response %d %+v", code, resp)
+	}
+
+	failure := new(http.Failure)
+	if code := put(`{}`, "", failure); code != nethttp.StatusBadRequest || failure.Code != http.ParamBindError || len(failure.Details) != 2 {
+		t.Fatalf("unexpected response %d %+v", code, failure)
+	}
+
+	failure = new(http.Failure)
+	if code := put(`{"name":"forbidden"}`, "t1", failure); code != nethttp.StatusForbidden || failure.Code != 20001 {
+		t.Fatalf("unexpected response %d %+v", code, failure)
+	}
+}
+
+func TestHandleSignature(t *testing.T) {
+	// 每个用例只违反一条规则
+	cases := map[string]interface{}{
+		"not a func":       updateItemRequest{},
+		"missing context":  func(req *updateItemRequest) http.Error { return nil },
+		"non-pointer req":  func(ctx http.Context, req updateItemRequest) http.Error { return nil },
+		"non-struct req":   func(ctx http.Context, req *string) http.Error { return nil },
+		"plain error":      func(ctx http.Context, req *updateItemRequest) error { return nil },
+		"no results":       func(ctx http.Context, req *updateItemRequest) {},
+		"too many results": func(ctx http.Context, req *updateItemRequest) (int, int, http.Error) { return 0, 0, nil },
+		"error not last":   func(ctx http.Context, req *updateItemRequest) (http.Error, *updateItemResponse) { return nil, nil },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected panic for invalid signature")
+				}
+			}()
+			http.Handle(fn)
+		})
+	}
+
+	// 合法签名不会 panic
+	http.Handle(func(ctx http.Context, req *updateItemRequest) http.Error { return nil })
+}
@@ -0,0 +1,51 @@
+package test
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mel2oo/juice/transport/http/middleware/notify"
+	"github.com/mel2oo/juice/transport/http/middleware/notify/templates"
+)
+
+// 旧版 PanicMail 仍按原有字段渲染
+func TestDeprecatedPanicMail(t *testing.T) {
+	tpl, err := template.New("panic").Parse(templates.PanicMail)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	err = tpl.Execute(buf, map[string]interface{}{
+		"ID":    "t1",
+		"URL":   "/panic",
+		"Msg":   "boom",
+		"Stack": "stack",
+		"Year":  2026,
+	})
+	if err != nil || !strings.Contains(buf.String(), "/panic") || !strings.Contains(buf.String(), "boom") {
+		t.Fatalf("unexpected render %v", err)
+	}
+}
+
+// Senders 可交给 juice.Server 管理，Stop 后 Start 返回
+func TestPanicNotifySenders(t *testing.T) {
+	srv := notify.Senders()
+	started := make(chan error, 1)
+	go func() { started <- srv.Start() }()
+
+	if err := srv.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-started:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after Stop")
+	}
+}
@@ -0,0 +1,45 @@
+package test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/pkg/logger/observer"
+	"github.com/mel2oo/juice/transport/http"
+)
+
+func TestObserveHandlerLogs(t *testing.T) {
+	log, logs := observer.New(logger.DebugLevel)
+
+	mux, err := http.NewMux(
+		http.WithLogger(log),
+		http.WithDisablePProf(),
+		http.WithDisableproPrometheus(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Group("").GET("/hello", func(ctx http.Context) {
+		ctx.Logger().Infow("say hello", "name", "juice")
+		ctx.Payload("hello")
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	if _, err := srv.Client().Get(srv.URL + "/hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	hello := logs.FilterMessage("say hello").All()
+	if len(hello) != 1 {
+		t.Fatalf("expected handler log, got %+v", logs.All())
+	}
+	if v, _ := hello[0].Field("route"); v != "/hello" {
+		t.Fatalf("request-scoped fields missing: %+v", hello[0].Fields)
+	}
+	if logs.FilterMessage("interceptor").FilterField(logger.Int("http_code", 200)).Len() != 1 {
+		t.Fatalf("expected interceptor log, got %+v", logs.All())
+	}
+}
@@ -0,0 +1,158 @@
+package test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mel2oo/juice/transport/http"
+)
+
+func TestOpenAPI(t *testing.T) {
+	mux, err := http.NewMux(
+		http.WithDisablePProf(),
+		http.WithDisableproPrometheus(),
+		http.WithDisableLogger(),
+		http.WithOpenAPI(http.OpenAPIInfo{Title: "items", Version: "v1"}),
+		http.WithOpenAPIViewer(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := mux.Group("/items")
+	items.Handle(nethttp.MethodPut, "/:id", func(ctx http.Context, req *updateItemRequest) (*updateItemResponse, http.Error) {
+		return nil, nil
+	}, http.Summary("update item"), http.Tags("item"), http.Errors(http.NewError(nethttp.StatusForbidden, 20001, "forbidden")))
+	items.GET("/:id/raw", func(ctx http.Context) {})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/system/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title string `json:"title"`
+		} `json:"info"`
+		Paths map[string]map[string]struct {
+			Summary    string   `json:"summary"`
+			Tags       []string `json:"tags"`
+			Parameters []struct {
+				Name     string `json:"name"`
+				In       string `json:"in"`
+				Required bool   `json:"required"`
+			} `json:"parameters"`
+			RequestBody struct {
+				Content map[string]struct {
+					Schema struct {
+						Required   []string               `json:"required"`
+						Properties map[string]interface{} `json:"properties"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"requestBody"`
+			Responses map[string]json.RawMessage `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+
+	op, ok := doc.Paths["/items/{id}"]["put"]
+	if doc.OpenAPI != "3.0.3" || doc.Info.Title != "items" || !ok || op.Summary != "update item" || op.Tags[0] != "item" {
+		t.Fatalf("unexpected document %+v", doc)
+	}
+	if len(op.Parameters) != 3 || op.Parameters[0].Name != "id" || op.Parameters[0].In != "path" ||
+		op.Parameters[1].Name != "X-Token" || op.Parameters[1].In != "header" || op.Parameters[2].In != "query" {
+		t.Fatalf("unexpected parameters %+v", op.Parameters)
+	}
+	body := op.RequestBody.Content["application/json"].Schema
+	if len(body.Properties) != 2 || len(body.Required) != 1 || body.Required[0] != "name" {
+		t.Fatalf("unexpected request body %+v", body)
+	}
+	for _, code := range []string{"200", "400", "403"} {
+		if _, ok := op.Responses[code]; !ok {
+			t.Fatalf("missing response %s: %+v", code, op.Responses)
+		}
+	}
+	for _, name := range []string{"updateItemResponse", "Failure", "FieldError"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Fatalf("missing schema %s", name)
+		}
+	}
+	if raw, ok := doc.Paths["/items/{id}/raw"]["get"]; !ok || len(raw.Parameters) != 1 {
+		t.Fatalf("unexpected plain route %+v", doc.Paths)
+	}
+	for p := range doc.Paths {
+		if strings.HasPrefix(p, "/system") {
+			t.Fatalf("system route %s in document", p)
+		}
+	}
+
+	// 默认的浏览页面内置，不加载外部资源
+	viewer, err := srv.Client().Get(srv.URL + "/system/openapi")
+	if err != nil || viewer.StatusCode != nethttp.StatusOK {
+		t.Fatalf("viewer: %v", err)
+	}
+	page, _ := ioutil.ReadAll(viewer.Body)
+	viewer.Body.Close()
+	if !strings.Contains(string(page), `fetch("openapi.json")`) || strings.Contains(string(page), "https://") {
+		t.Fatalf("unexpected viewer page:\n%s", page)
+	}
+
+	filename := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := mux.WriteOpenAPI(filename); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := ioutil.ReadFile(filename)
+	if !strings.HasPrefix(string(raw), "openapi: 3.0.3\n") || !strings.Contains(string(raw), "/items/{id}:") {
+		t.Fatalf("unexpected yaml:\n%s", raw)
+	}
+}
+
+func TestOpenAPIViewerAssets(t *testing.T) {
+	mux, err := http.NewMux(
+		http.WithDisablePProf(),
+		http.WithDisableproPrometheus(),
+		http.WithDisableLogger(),
+		http.WithOpenAPI(http.OpenAPIInfo{Title: "items", Version: "v1"}),
+		http.WithOpenAPIViewer(http.SwaggerUIAssets{
+			CSS:          "/static/swagger-ui.css",
+			JS:           "/static/swagger-ui-bundle.js",
+			JSIntegrity:  "sha384-abc",
+			CSSIntegrity: "sha384-def",
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/system/openapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	page, _ := ioutil.ReadAll(resp.Body)
+	for _, want := range []string{
+		`href="/static/swagger-ui.css" integrity="sha384-def" crossorigin="anonymous"`,
+		`src="/static/swagger-ui-bundle.js" integrity="sha384-abc" crossorigin="anonymous"`,
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Fatalf("page missing %q:\n%s", want, page)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package test
+
+import (
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/transport/http"
+)
+
+func TestRateLimiter(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger(),
+		http.WithRateLimit(http.RateLimitConfig{Limit: http.PerMinute(100)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := mux.Group("/api", http.RateLimiter(http.RateLimitConfig{
+		Limit: http.RateLimit{Rate: 0.01, Burst: 2},
+		Key:   http.KeyByHeader("X-API-Key"),
+	}))
+	api.GET("/ping", func(ctx http.Context) {
+		ctx.Payload("pong")
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	get := func(key string) *nethttp.Response {
+		req, _ := nethttp.NewRequest(nethttp.MethodGet, srv.URL+"/api/ping", nil)
+		req.Header.Set("X-API-Key", key)
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	for i := 0; i < 2; i++ {
+		if resp := get("a"); resp.StatusCode != nethttp.StatusOK {
+			t.Fatalf("request %d: unexpected status %d", i, resp.StatusCode)
+		}
+	}
+
+	resp := get("a")
+	if resp.StatusCode != nethttp.StatusTooManyRequests {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+	if resp.Header.Get("RateLimit-Limit") != "2" || resp.Header.Get("RateLimit-Remaining") != "0" || resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("unexpected headers %v", resp.Header)
+	}
+
+	resp = get("b")
+	if resp.StatusCode != nethttp.StatusOK || resp.Header.Get("RateLimit-Remaining") != "1" {
+		t.Fatalf("unexpected response for another key %d %v", resp.StatusCode, resp.Header)
+	}
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	store := http.NewMemoryRateLimitStore(2)
+	limit := http.RateLimit{Burst: 1}
+
+	if res, _ := store.Take("a", limit); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if res, _ := store.Take("a", limit); res.Allowed {
+		t.Fatalf("unexpected result %+v", res)
+	}
+
+	store.Take("b", limit)
+	store.Take("c", limit)
+	if store.Len() != 2 {
+		t.Fatalf("unexpected len %d", store.Len())
+	}
+
+	// a 已被淘汰，重新获得完整的令牌桶
+	if res, _ := store.Take("a", limit); !res.Allowed {
+		t.Fatalf("evicted key should be reset, got %+v", res)
+	}
+}
+
+func TestRateLimitClientIP(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger(),
+		http.WithRateLimit(http.RateLimitConfig{Limit: http.RateLimit{Burst: 1}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Group("/proxied", http.RateLimiter(http.RateLimitConfig{
+		Limit: http.RateLimit{Burst: 1},
+		Key:   http.KeyByClientIP("127.0.0.1", "10.0.0.0/8"),
+	})).GET("/ping", func(ctx http.Context) {
+		ctx.Payload("pong")
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	get := func(path, forwarded string) int {
+		req, _ := nethttp.NewRequest(nethttp.MethodGet, srv.URL+path, nil)
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// 健康检查不计入全局限额
+	for i := 0; i < 3; i++ {
+		if code := get("/system/health", "2.2.2.2"); code != nethttp.StatusOK {
+			t.Fatalf("health check should not be limited, got %d", code)
+		}
+	}
+	if code := get("/proxied/ping", "1.1.1.1"); code != nethttp.StatusOK {
+		t.Fatalf("unexpected status %d", code)
+	}
+	// 全局限流按对端 IP，伪造 X-Forwarded-For 不能绕过
+	if code := get("/proxied/ping", "2.2.2.2"); code != nethttp.StatusTooManyRequests {
+		t.Fatalf("spoofed X-Forwarded-For should not bypass the global limit, got %d", code)
+	}
+}
+
+// 只有健康检查免于全局限流，/system 下的管理接口仍然限流
+func TestRateLimitSystemRoutes(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger(),
+		http.WithLoggerAdmin(logger.NewRegistry()),
+		http.WithRateLimit(http.RateLimitConfig{Limit: http.RateLimit{Burst: 1}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	get := func(path string) int {
+		resp, err := srv.Client().Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := get("/system/loggers"); code != nethttp.StatusOK {
+		t.Fatalf("unexpected status %d", code)
+	}
+	if code := get("/system/loggers"); code != nethttp.StatusTooManyRequests {
+		t.Fatalf("logger admin should be limited, got %d", code)
+	}
+	if code := get("/system/health"); code != nethttp.StatusOK {
+		t.Fatalf("health check should not be limited, got %d", code)
+	}
+}
+
+func TestKeyByClientIP(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := http.KeyByClientIP("127.0.0.1", "10.0.0.0/8")
+	mux.Group("").GET("/key", func(ctx http.Context) {
+		ctx.Payload(key(ctx))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for forwarded, want := range map[string]string{
+		"":                           `"ip:127.0.0.1"`,
+		"1.1.1.1":                    `"ip:1.1.1.1"`,
+		"6.6.6.6, 1.1.1.1, 10.0.0.2": `"ip:1.1.1.1"`,
+	} {
+		req, _ := nethttp.NewRequest(nethttp.MethodGet, srv.URL+"/key", nil)
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != want {
+			t.Fatalf("X-Forwarded-For %q: got %s, want %s", forwarded, body, want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mel2oo/juice/pkg/httpclient"
+	"github.com/mel2oo/juice/pkg/signature"
+	"github.com/mel2oo/juice/transport/http"
+)
+
+func TestSignatureEndToEnd(t *testing.T) {
+	verifier := signature.NewVerifier(signature.NewKeyRing().Set("k1", []byte("secret")))
+
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Group("/internal", http.WrapSignatureHandler(verifier)).POST("/orders", func(ctx http.Context) {
+		ctx.Payload("ok")
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	url := srv.URL + "/internal/orders?source=test"
+	body, err := httpclient.PostJSON(url, []byte(`{"id":1}`), httpclient.WithSigner(signature.NewSigner("k1", []byte("secret"))))
+	if err != nil || string(body) != `"ok"` {
+		t.Fatalf("signed request got %s %v", body, err)
+	}
+
+	for name, opts := range map[string][]httpclient.Option{
+		"unsigned":     nil,
+		"wrong secret": {httpclient.WithSigner(signature.NewSigner("k1", []byte("other")))},
+		"unknown key":  {httpclient.WithSigner(signature.NewSigner("k2", []byte("secret")))},
+	} {
+		_, err := httpclient.PostJSON(url, []byte(`{"id":1}`), opts...)
+		if reply, ok := httpclient.ToReplyErr(err); !ok || reply.StatusCode() != nethttp.StatusUnauthorized {
+			t.Fatalf("%s request got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mel2oo/juice/transport/http"
+)
+
+type createUserRequest struct {
+	Name     string   `json:"name" binding:"required,max=8"`
+	Age      int      `json:"age" binding:"gte=18"`
+	Tags     []string `json:"tags" binding:"dive,alphanum"`
+	Password string   `json:"password"`
+	Confirm  string   `json:"confirm"`
+}
+
+func (r *createUserRequest) Validate() error {
+	if r.Password != r.Confirm {
+		return &http.ValidationError{Fields: []http.FieldError{{Field: "confirm", Rule: "eqfield", Param: "password"}}}
+	}
+	return nil
+}
+
+func TestBindValidation(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Group("").POST("/users", func(ctx http.Context) {
+		req := new(createUserRequest)
+		if err := ctx.ShouldBindJSON(req); err != nil {
+			ctx.AbortWithError(http.NewError(nethttp.StatusBadRequest, http.ParamBindError, http.Text(http.ParamBindError)).WithErr(err))
+			return
+		}
+		ctx.Payload(req)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	post := func(body, lang string) (int, *http.Failure) {
+		req, _ := nethttp.NewRequest(nethttp.MethodPost, srv.URL+"/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if lang != "" {
+			req.Header.Set("Accept-Language", lang)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		failure := new(http.Failure)
+		json.NewDecoder(resp.Body).Decode(failure)
+		return resp.StatusCode, failure
+	}
+
+	code, failure := post(`{"name":"juice-too-long","age":16,"tags":["ok","a b"]}`, "en-US,en;q=0.9")
+	if code != nethttp.StatusBadRequest || failure.Code != http.ParamBindError || len(failure.Details) != 3 {
+		t.Fatalf("unexpected response %d %+v", code, failure)
+	}
+	want := []http.FieldError{
+		{Field: "name", Rule: "max", Param: "8", Message: "name must be a maximum of 8 characters in length"},
+		{Field: "age", Rule: "gte", Param: "18", Message: "age must be 18 or greater"},
+		{Field: "tags[1]", Rule: "alphanum", Message: "tags[1] can only contain alphanumeric characters"},
+	}
+	for i := range want {
+		if failure.Details[i] != want[i] {
+			t.Fatalf("detail %d: got %+v, want %+v", i, failure.Details[i], want[i])
+		}
+	}
+
+	_, failure = post(`{"name":"juice","age":20,"password":"a","confirm":"b"}`, "")
+	if len(failure.Details) != 1 || failure.Details[0].Field != "confirm" || failure.Details[0].Message != "confirm校验失败(eqfield)" {
+		t.Fatalf("unexpected details %+v", failure.Details)
+	}
+
+	if code, _ := post(`{"name":"juice","age":20}`, ""); code != nethttp.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+}
+
+func TestValidationErrorUnwrap(t *testing.T) {
+	err := http.NewError(nethttp.StatusBadRequest, http.ParamBindError, "").
+		WithErr(&http.ValidationError{Fields: []http.FieldError{{Field: "id", Rule: "required"}}})
+
+	var verr *http.ValidationError
+	if !errors.As(err.GetErr(), &verr) || verr.Localize("en").Fields[0].Message != "id is a required field" {
+		t.Fatalf("unexpected error %v", err.GetErr())
+	}
+}
+
+type pageQuery struct {
+	Page int `json:"page" binding:"gte=1"`
+}
+
+type orderItem struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type listOrdersRequest struct {
+	pageQuery
+	Items []orderItem `json:"items" binding:"dive"`
+}
+
+func TestEmbeddedFieldPath(t *testing.T) {
+	mux, err := http.NewMux(http.WithDisablePProf(), http.WithDisableproPrometheus(), http.WithDisableLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Group("").POST("/orders", func(ctx http.Context) {
+		var verr *http.ValidationError
+		if err := ctx.ShouldBindJSON(new(listOrdersRequest)); errors.As(err, &verr) {
+			ctx.Payload(verr.Fields)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := srv.Client().Post(srv.URL+"/orders", "application/json", strings.NewReader(`{"page":0,"items":[{"name":""}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var fields []http.FieldError
+	json.NewDecoder(resp.Body).Decode(&fields)
+	// 嵌入结构体的字段名不出现在路径中
+	if len(fields) != 2 || fields[0].Field != "page" || fields[1].Field != "items[0].name" {
+		t.Fatalf("unexpected fields %+v", fields)
+	}
+}
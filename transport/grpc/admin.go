@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/transport/grpc/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// LoggerAdminServiceName 日志级别管理服务的全名，方法：
+//
+//	ListLevels(google.protobuf.Empty) returns (google.protobuf.Struct)   {"loggers":[{"name":"grpc","level":"info"}]}
+//	SetLevel(google.protobuf.Struct) returns (google.protobuf.Struct)    {"name":"grpc","level":"debug","revert_after":"10m"}
+const LoggerAdminServiceName = "juice.system.LoggerAdmin"
+
+// RegisterLoggerAdmin 在 s 上注册日志级别管理服务，registry 为 nil 时使用 logger.DefaultRegistry；
+// interceptors 依次在管理方法前执行，只作用于该服务，用于鉴权，返回错误时拒绝调用
+func RegisterLoggerAdmin(s *grpc.Server, registry *logger.Registry, interceptors ...grpc.UnaryServerInterceptor) {
+	if registry == nil {
+		registry = logger.DefaultRegistry
+	}
+	srv := &loggerAdminServer{registry: registry}
+	if len(interceptors) > 0 {
+		srv.interceptor = middleware.ChainUnaryServer(interceptors...)
+	}
+	s.RegisterService(&loggerAdminServiceDesc, srv)
+}
+
+type loggerAdminServer struct {
+	registry    *logger.Registry
+	interceptor grpc.UnaryServerInterceptor
+}
+
+// handle 依次经过 Server 的拦截器与服务自身的拦截器后调用 handler
+func (s *loggerAdminServer) handle(ctx context.Context, req interface{}, method string, interceptor grpc.UnaryServerInterceptor, handler grpc.UnaryHandler) (interface{}, error) {
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/" + LoggerAdminServiceName + "/" + method,
+	}
+	if s.interceptor != nil {
+		inner := handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return s.interceptor(ctx, req, info, inner)
+		}
+	}
+	if interceptor == nil {
+		return handler(ctx, req)
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func (s *loggerAdminServer) ListLevels(ctx context.Context, _ *emptypb.Empty) (*structpb.Struct, error) {
+	return toStruct(map[string]interface{}{"loggers": s.registry.Levels()})
+}
+
+func (s *loggerAdminServer) SetLevel(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	fields := req.GetFields()
+
+	level, err := logger.ParseLevel(fields["level"].GetStringValue())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var revertAfter time.Duration
+	if v := fields["revert_after"].GetStringValue(); v != "" {
+		if revertAfter, err = time.ParseDuration(v); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	info, err := s.registry.SetLevel(fields["name"].GetStringValue(), level, revertAfter)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	logger.FromContext(ctx).Warnw("logger level changed",
+		"logger", info.Name,
+		"level", info.Level,
+		"revert_after", revertAfter,
+	)
+	return toStruct(info)
+}
+
+// toStruct 经 JSON 将 v 转换为 structpb.Struct
+func toStruct(v interface{}) (*structpb.Struct, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s, nil
+}
+
+var loggerAdminServiceDesc = grpc.ServiceDesc{
+	ServiceName: LoggerAdminServiceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListLevels",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(emptypb.Empty)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				s := srv.(*loggerAdminServer)
+				return s.handle(ctx, in, "ListLevels", interceptor, func(ctx context.Context, req interface{}) (interface{}, error) {
+					return s.ListLevels(ctx, req.(*emptypb.Empty))
+				})
+			},
+		},
+		{
+			MethodName: "SetLevel",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(structpb.Struct)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				s := srv.(*loggerAdminServer)
+				return s.handle(ctx, in, "SetLevel", interceptor, func(ctx context.Context, req interface{}) (interface{}, error) {
+					return s.SetLevel(ctx, req.(*structpb.Struct))
+				})
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/mel2oo/juice/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// AcceptLanguageMetadataKey 客户端在 metadata 中指定错误描述信息的语言
+const AcceptLanguageMetadataKey = "accept-language"
+
+// UnaryServerErrorInterceptor 将 handler 返回的 *errors.Error 转换为 gRPC status，
+// 描述信息按 metadata 中的 accept-language 本地化，业务码等写入 details(见 errors.Error.Status)
+func UnaryServerErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, toStatusError(ctx, err)
+	}
+}
+
+// StreamServerErrorInterceptor 同 UnaryServerErrorInterceptor
+func StreamServerErrorInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return toStatusError(ss.Context(), handler(srv, ss))
+	}
+}
+
+func toStatusError(ctx context.Context, err error) error {
+	e, ok := errors.FromError(err)
+	if !ok {
+		return err
+	}
+
+	locale := errors.DefaultLocale
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(AcceptLanguageMetadataKey); len(v) > 0 {
+			locale = errors.NegotiateLocale(v[0])
+		}
+	}
+	return e.Status(locale).Err()
+}
+
+// UnaryClientErrorInterceptor 将服务端由 UnaryServerErrorInterceptor 转换的 status 还原为 *errors.Error，
+// 调用方可通过 errors.FromError、errors.Is 判断业务码；其他错误原样返回
+func UnaryClientErrorInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return fromStatusError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientErrorInterceptor 同 UnaryClientErrorInterceptor，作用于建立流及 RecvMsg 返回的错误
+func StreamClientErrorInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, fromStatusError(err)
+		}
+		return &errorClientStream{cs}, nil
+	}
+}
+
+type errorClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *errorClientStream) RecvMsg(m interface{}) error {
+	return fromStatusError(s.ClientStream.RecvMsg(m))
+}
+
+func fromStatusError(err error) error {
+	if e, ok := errors.FromError(err); ok {
+		return e
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mel2oo/juice/pkg/errors"
+	"github.com/mel2oo/juice/pkg/limiter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PriorityFunc 按方法全名(如 /grpc.health.v1.Health/Check)返回请求的优先级
+type PriorityFunc func(ctx context.Context, fullMethod string) limiter.Priority
+
+// DefaultPriority 健康检查及日志级别管理服务为 PriorityCritical，其他为 PriorityNormal
+func DefaultPriority(ctx context.Context, fullMethod string) limiter.Priority {
+	if strings.HasPrefix(fullMethod, "/grpc.health.v1.Health/") ||
+		strings.HasPrefix(fullMethod, "/"+LoggerAdminServiceName+"/") {
+		return limiter.PriorityCritical
+	}
+	return limiter.PriorityNormal
+}
+
+// UnaryServerLimiterInterceptor 并发数超出请求优先级可用的上限时返回业务码为 limiter.ServiceBusy 的 ResourceExhausted；
+// handler 返回 DeadlineExceeded、Unavailable 或请求超时时视为过载，自适应算法会降低并发上限，
+// ResourceExhausted(含内层限制返回的 ServiceBusy)不计入。priority 为 nil 时使用 DefaultPriority
+func UnaryServerLimiterInterceptor(l *limiter.Limiter, priority PriorityFunc) grpc.UnaryServerInterceptor {
+	if priority == nil {
+		priority = DefaultPriority
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		token, ok := l.Acquire(priority(ctx, info.FullMethod))
+		if !ok {
+			return nil, serverBusy(ctx)
+		}
+		defer func() { release(ctx, token, err) }()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerLimiterInterceptor 同 UnaryServerLimiterInterceptor，流在整个生命周期内占用并发额度
+func StreamServerLimiterInterceptor(l *limiter.Limiter, priority PriorityFunc) grpc.StreamServerInterceptor {
+	if priority == nil {
+		priority = DefaultPriority
+	}
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		token, ok := l.Acquire(priority(ss.Context(), info.FullMethod))
+		if !ok {
+			return serverBusy(ss.Context())
+		}
+		defer func() { release(ss.Context(), token, err) }()
+
+		return handler(srv, ss)
+	}
+}
+
+// serverBusy 返回按 accept-language 本地化的 ServiceBusy 错误
+func serverBusy(ctx context.Context) error {
+	return toStatusError(ctx, errors.New(limiter.ServiceBusy))
+}
+
+func release(ctx context.Context, token *limiter.Token, err error) {
+	if e, ok := errors.FromError(err); ok && e.Code == limiter.ServiceBusy {
+		token.Done()
+		return
+	}
+	switch status.Code(err) {
+	case codes.DeadlineExceeded, codes.Unavailable:
+		token.Drop()
+	default:
+		if ctx.Err() == context.DeadlineExceeded {
+			token.Drop()
+			return
+		}
+		token.Done()
+	}
+}
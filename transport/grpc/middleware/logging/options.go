@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/mel2oo/juice/pkg/logger"
+	lzap "github.com/mel2oo/juice/pkg/logger/zap"
 	"github.com/mel2oo/juice/transport/grpc/middleware/logging/ctxzap"
 
 	"go.uber.org/zap"
@@ -210,11 +211,9 @@ type MessageProducer func(ctx context.Context, msg string, level logger.Level, c
 // DefaultMessageProducer writes the default message
 func DefaultMessageProducer(ctx context.Context, msg string, level logger.Level, code codes.Code, err error, duration zapcore.Field) {
 	// re-extract logger from newCtx, as it may have extra fields that changed in the holder.
-	// ctxzap.Extract(ctx).Check(level, msg).Write(
-	// 	zap.Error(err),
-	// 	zap.String("grpc.code", code.String()),
-	// 	duration,
-	// )
-
-	ctxzap.Extract(ctx).Sugar().Infof("hello world -- cc")
+	ctxzap.Extract(ctx).Check(lzap.ToZapLevel(level), msg).Write(
+		zap.Error(err),
+		zap.String("grpc.code", code.String()),
+		duration,
+	)
 }
@@ -2,15 +2,24 @@ package grpc_logging
 
 import (
 	"context"
-	"fmt"
 	"path"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/mel2oo/juice/pkg/logger"
+	lzap "github.com/mel2oo/juice/pkg/logger/zap"
 	"github.com/mel2oo/juice/transport/grpc/middleware"
+	"github.com/mel2oo/juice/transport/grpc/middleware/logging/ctxzap"
+	"github.com/mel2oo/juice/transport/grpc/middleware/tags"
+	"github.com/mel2oo/juice/transport/http/middleware/trace"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 )
 
 var (
@@ -19,57 +28,129 @@ var (
 
 	// ServerField is used in every server-side log statement made through grpc_zap.Can be overwritten before initialization.
 	ServerField = zap.String("span.kind", "server")
+
+	// TraceIDMetadataKey is the incoming metadata key carrying the trace id, the same header the http mux uses.
+	TraceIDMetadataKey = strings.ToLower(trace.Header)
+
+	// UserIDMetadataKey is the incoming metadata key carrying the caller's user id, if any.
+	UserIDMetadataKey = "user-id"
 )
 
 // UnaryServerInterceptor returns a new unary server interceptors that adds zap.Logger to the context.
-func UnaryServerInterceptor(logger logger.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+//
+// The handler receives the incoming context enriched with request tags, a ctxzap logger and a request-scoped
+// logger.Logger (see logger.FromContext); deadlines, cancellation, peer info and metadata are preserved.
+func UnaryServerInterceptor(log logger.Logger, opts ...Option) grpc.UnaryServerInterceptor {
 	o := evaluateServerOpt(opts)
+	zl := lzap.ToZap(log)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		newCtx := context.Background()
+		startTime := time.Now()
+		newCtx := newLoggerForCall(ctx, log, zl, info.FullMethod, startTime, o.timestampFormat)
 
 		resp, err := handler(newCtx, req)
 		if !o.shouldLog(info.FullMethod, err) {
 			return resp, err
 		}
 
-		code := o.codeFunc(err)
-		level := o.levelFunc(code)
-
-		logger.Log(level, fmt.Sprintf("server transport: %s | method: %s | msg: %s | code: %s",
-			"GRPC",
-			info.FullMethod,
-			"finished unarying call",
-			code))
-
+		ctxzap.AddFields(newCtx,
+			zap.Int("grpc.request.size", messageSize(req)),
+			zap.Int("grpc.response.size", messageSize(resp)),
+		)
+		logFinalServerLine(newCtx, o, startTime, err, "finished unary call")
 		return resp, err
 	}
 }
 
 // StreamServerInterceptor returns a new streaming server interceptor that adds zap.Logger to the context.
-func StreamServerInterceptor(logger logger.Logger, opts ...Option) grpc.StreamServerInterceptor {
+func StreamServerInterceptor(log logger.Logger, opts ...Option) grpc.StreamServerInterceptor {
 	o := evaluateServerOpt(opts)
+	zl := lzap.ToZap(log)
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		newCtx := context.Background()
+		startTime := time.Now()
+		newCtx := newLoggerForCall(stream.Context(), log, zl, info.FullMethod, startTime, o.timestampFormat)
+
 		wrapped := middleware.WrapServerStream(stream)
 		wrapped.WrappedContext = newCtx
+		sized := &sizedServerStream{ServerStream: wrapped}
 
-		err := handler(srv, wrapped)
+		err := handler(srv, sized)
 		if !o.shouldLog(info.FullMethod, err) {
 			return err
 		}
-		code := o.codeFunc(err)
-		level := o.levelFunc(code)
-
-		logger.Log(level, fmt.Sprintf("server transport: %s | method: %s | msg: %s | code: %s",
-			"GRPC",
-			info.FullMethod,
-			"finished streaming call",
-			code))
 
+		ctxzap.AddFields(newCtx,
+			zap.Int("grpc.request.msgs", sized.recvMsgs),
+			zap.Int("grpc.request.size", sized.recvSize),
+			zap.Int("grpc.response.msgs", sized.sentMsgs),
+			zap.Int("grpc.response.size", sized.sentSize),
+		)
+		logFinalServerLine(newCtx, o, startTime, err, "finished streaming call")
 		return err
 	}
 }
 
+func logFinalServerLine(ctx context.Context, o *options, startTime time.Time, err error, msg string) {
+	code := o.codeFunc(err)
+	level := o.levelFunc(code)
+	duration := o.durationFunc(time.Since(startTime))
+	o.messageFunc(ctx, msg, level, code, err, duration)
+}
+
+// newLoggerForCall enriches ctx with request tags (peer address), a ctxzap logger carrying the call fields and
+// a request-scoped logger.Logger retrievable with logger.FromContext.
+func newLoggerForCall(ctx context.Context, log logger.Logger, zl *zap.Logger, fullMethodString string, start time.Time, timestampFormat string) context.Context {
+	if tags.Extract(ctx) == tags.NoopTags {
+		t := tags.NewTags()
+		if p, ok := peer.FromContext(ctx); ok {
+			t.Set("peer.address", p.Addr.String())
+		}
+		ctx = tags.SetInContext(ctx, t)
+	}
+
+	requestFields := newRequestFields(ctx, fullMethodString)
+
+	zapFields := append(serverCallFields(fullMethodString), zap.String("grpc.start_time", start.Format(timestampFormat)))
+	if d, ok := ctx.Deadline(); ok {
+		zapFields = append(zapFields, zap.String("grpc.request.deadline", d.Format(timestampFormat)))
+	}
+	zapFields = append(zapFields, lzap.ToZapFields(requestFields...)...)
+
+	ctx = ctxzap.ToContext(ctx, zl.With(zapFields...))
+	return logger.ToContext(ctx, log.With(requestFields...))
+}
+
+// newRequestFields returns trace_id, span_id, route, method and, when present in the incoming metadata, user_id.
+func newRequestFields(ctx context.Context, fullMethodString string) []logger.Field {
+	var traceID, userID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(TraceIDMetadataKey); len(v) > 0 {
+			traceID = v[0]
+		}
+		if v := md.Get(UserIDMetadataKey); len(v) > 0 {
+			userID = v[0]
+		}
+	}
+	if traceID == "" {
+		traceID = trace.New("").ID()
+	}
+
+	fields := []logger.Field{
+		logger.String("trace_id", traceID),
+		logger.String("span_id", trace.NewSpanID()),
+		logger.String("route", fullMethodString),
+		logger.String("method", path.Base(fullMethodString)),
+	}
+	// 与 HTTP 一致以 int64 记录 user_id，无法解析时记录到 user_id_raw，避免同一字段出现不同类型
+	if userID != "" {
+		if id, err := strconv.ParseInt(userID, 10, 64); err == nil {
+			fields = append(fields, logger.Int64("user_id", id))
+		} else {
+			fields = append(fields, logger.String("user_id_raw", userID))
+		}
+	}
+	return fields
+}
+
 func serverCallFields(fullMethodString string) []zapcore.Field {
 	service := path.Dir(fullMethodString)[1:]
 	method := path.Base(fullMethodString)
@@ -80,3 +161,37 @@ func serverCallFields(fullMethodString string) []zapcore.Field {
 		zap.String("grpc.method", method),
 	}
 }
+
+func messageSize(m interface{}) int {
+	if p, ok := m.(proto.Message); ok {
+		return proto.Size(p)
+	}
+	return 0
+}
+
+// sizedServerStream counts the messages and bytes that pass through a server stream.
+type sizedServerStream struct {
+	grpc.ServerStream
+	sentMsgs int
+	sentSize int
+	recvMsgs int
+	recvSize int
+}
+
+func (s *sizedServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sentMsgs++
+		s.sentSize += messageSize(m)
+	}
+	return err
+}
+
+func (s *sizedServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.recvMsgs++
+		s.recvSize += messageSize(m)
+	}
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/mel2oo/juice/pkg/limiter"
 	"github.com/mel2oo/juice/pkg/logger"
 	"github.com/mel2oo/juice/pkg/logger/zap"
 	"github.com/mel2oo/juice/transport/grpc/middleware"
@@ -43,6 +44,26 @@ func Middleware(gm grpc.UnaryServerInterceptor) ServerOption {
 	}
 }
 
+// ConcurrencyLimiter 使用 l 限制并发请求数(见 UnaryServerLimiterInterceptor)，priority 为 nil 时使用 DefaultPriority
+func ConcurrencyLimiter(l *limiter.Limiter, priority PriorityFunc) ServerOption {
+	return func(s *Server) {
+		s.limiter = l
+		s.priority = priority
+	}
+}
+
+// LoggerAdmin 注册日志级别管理服务(见 LoggerAdminServiceName)，registry 为 nil 时使用 logger.DefaultRegistry，
+// Server 的 logger 未注册时以 "grpc" 注册；interceptors 只作用于管理服务，用于鉴权
+func LoggerAdmin(registry *logger.Registry, interceptors ...grpc.UnaryServerInterceptor) ServerOption {
+	return func(s *Server) {
+		if registry == nil {
+			registry = logger.DefaultRegistry
+		}
+		s.loggerRegistry = registry
+		s.loggerInterceptors = interceptors
+	}
+}
+
 type Server struct {
 	*grpc.Server
 	// lis        net.Listener
@@ -51,6 +72,11 @@ type Server struct {
 	timeout    time.Duration
 	log        logger.Logger
 	middleware grpc.UnaryServerInterceptor
+
+	loggerRegistry     *logger.Registry
+	loggerInterceptors []grpc.UnaryServerInterceptor
+	limiter            *limiter.Limiter
+	priority           PriorityFunc
 }
 
 func NewServer(opts ...ServerOption) *Server {
@@ -65,11 +91,26 @@ func NewServer(opts ...ServerOption) *Server {
 		o(srv)
 	}
 
-	srv.middleware = middleware.ChainUnaryServer(
+	interceptors := []grpc.UnaryServerInterceptor{
 		logging.UnaryServerInterceptor(srv.log),
-	)
+		UnaryServerErrorInterceptor(),
+	}
+	var serverOpts []grpc.ServerOption
+	if srv.limiter != nil {
+		// 先于日志等拦截器执行，过载时以最小的开销拒绝请求
+		interceptors = append([]grpc.UnaryServerInterceptor{UnaryServerLimiterInterceptor(srv.limiter, srv.priority)}, interceptors...)
+		serverOpts = append(serverOpts, grpc.StreamInterceptor(StreamServerLimiterInterceptor(srv.limiter, srv.priority)))
+	}
+	srv.middleware = middleware.ChainUnaryServer(interceptors...)
+
+	srv.Server = grpc.NewServer(append(serverOpts, grpc.UnaryInterceptor(srv.middleware))...)
 
-	srv.Server = grpc.NewServer(grpc.UnaryInterceptor(srv.middleware))
+	if registry := srv.loggerRegistry; registry != nil {
+		if _, ok := registry.Get("grpc"); !ok {
+			registry.Register("grpc", srv.log)
+		}
+		RegisterLoggerAdmin(srv.Server, registry, srv.loggerInterceptors...)
+	}
 
 	return srv
 }
@@ -1,27 +1,62 @@
 package http
 
+import (
+	"net/http"
+
+	"github.com/mel2oo/juice/pkg/errors"
+	"github.com/mel2oo/juice/pkg/limiter"
+	"google.golang.org/grpc/codes"
+)
+
 type Failure struct {
-	Code    int    `json:"code"`    // 业务码
-	Message string `json:"message"` // 描述信息
+	Code    int           `json:"code"`              // 业务码
+	Message string        `json:"message"`           // 描述信息
+	Details []FieldError  `json:"details,omitempty"` // 参数校验失败的字段明细
+	Extra   []interface{} `json:"extra,omitempty"`   // Error.WithDetails 附加的明细
 }
 
 const (
 	// 服务级错误码
 	ServerError     = 10001
 	TooManyRequests = 10002
+	ServiceBusy     = limiter.ServiceBusy // 由 pkg/limiter 注册，与 gRPC 共用
 	ParamBindError  = 10103
-	// AuthorizationError = 10104
+	SignatureError  = 10104
 	// CallHTTPError      = 10105
 )
 
-var codeText = map[int]string{
-	ServerError:     "Internal Server Error",
-	TooManyRequests: "Too Many Requests",
-	ParamBindError:  "参数信息有误",
-	// AuthorizationError: "签名信息有误",
-	// CallHTTPError:      "调用第三方 HTTP 接口失败",
+func init() {
+	errors.MustRegister(
+		errors.Code{
+			Code:       ServerError,
+			HTTPStatus: http.StatusInternalServerError,
+			GRPCCode:   codes.Internal,
+			Messages:   map[string]string{"zh": "Internal Server Error", "en": "Internal Server Error"},
+		},
+		errors.Code{
+			Code:       TooManyRequests,
+			HTTPStatus: http.StatusTooManyRequests,
+			GRPCCode:   codes.ResourceExhausted,
+			Messages:   map[string]string{"zh": "Too Many Requests", "en": "Too Many Requests"},
+			Retryable:  true,
+		},
+		errors.Code{
+			Code:       ParamBindError,
+			HTTPStatus: http.StatusBadRequest,
+			GRPCCode:   codes.InvalidArgument,
+			Messages:   map[string]string{"zh": "参数信息有误", "en": "Invalid parameters"},
+		},
+		errors.Code{
+			Code:       SignatureError,
+			HTTPStatus: http.StatusUnauthorized,
+			GRPCCode:   codes.Unauthenticated,
+			Messages:   map[string]string{"zh": "签名信息有误", "en": "Invalid signature"},
+		},
+		// CallHTTPError:      "调用第三方 HTTP 接口失败",
+	)
 }
 
+// Text 返回业务码在 errors.DefaultLocale 下的描述信息，业务码通过 errors.Register 注册
 func Text(code int) string {
-	return codeText[code]
+	return errors.Text(code)
 }
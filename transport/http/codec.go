@@ -0,0 +1,324 @@
+package http
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	MIMEJSON     = "application/json"
+	MIMEProtobuf = "application/x-protobuf"
+	MIMEXML      = "application/xml"
+	MIMEMsgPack  = "application/x-msgpack"
+	MIMEYAML     = "application/x-yaml"
+)
+
+// ErrNotProtoMessage protobuf 编解码的对象未实现 proto.Message
+var ErrNotProtoMessage = errors.New("http: value does not implement proto.Message")
+
+// Codec Payload、AbortWithError 的响应编码及 ShouldBind 的请求体解码
+type Codec interface {
+	// ContentType 响应的 Content-Type
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	codecMu sync.RWMutex
+	codecs  = make(map[string]Codec)
+	// registeredMediaTypes 保持注册顺序，用于 Accept 中 application/* 等通配的匹配
+	registeredMediaTypes []string
+)
+
+func init() {
+	RegisterCodec(jsonCodec{}, MIMEJSON)
+	RegisterCodec(protobufCodec{}, MIMEProtobuf, "application/protobuf", "application/vnd.google.protobuf")
+	RegisterCodec(xmlCodec{}, MIMEXML, "text/xml")
+	RegisterCodec(msgpackCodec{}, MIMEMsgPack, "application/msgpack")
+	RegisterCodec(yamlCodec{}, MIMEYAML, "application/yaml", "text/yaml")
+}
+
+// RegisterCodec 注册或替换 mediaTypes 对应的 Codec，mediaTypes 为空时使用 codec.ContentType() 的媒体类型
+func RegisterCodec(c Codec, mediaTypes ...string) {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{c.ContentType()}
+	}
+
+	codecMu.Lock()
+	defer codecMu.Unlock()
+
+	for _, mt := range mediaTypes {
+		mt = mediaType(mt)
+		if _, ok := codecs[mt]; !ok {
+			registeredMediaTypes = append(registeredMediaTypes, mt)
+		}
+		codecs[mt] = c
+	}
+}
+
+// CodecFor 返回 Content-Type 对应的 Codec，未注册时返回 nil
+func CodecFor(contentType string) Codec {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+
+	return codecs[mediaType(contentType)]
+}
+
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mt
+}
+
+// negotiateCodec 按 Accept 的 q 值选择 Codec，q 相同时具体类型优先于通配符、JSON 优先于其他类型；
+// 最佳匹配没有对应 Codec(如浏览器 Accept 中的 text/html)或为 */*、application/* 时，JSON 可以接受则使用 JSON，
+// 都不可用时仍使用 JSON
+func negotiateCodec(accept string) Codec {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+
+	if accept == "" {
+		return codecs[MIMEJSON]
+	}
+
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+	var candidates []candidate
+	// jsonQ 按最具体的匹配项(application/json > application/* > */*)确定 JSON 的 q 值
+	jsonQ, jsonSpecificity := 0.0, 0
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		c := candidate{mediaType: strings.ToLower(strings.TrimSpace(params[0])), q: 1}
+		for _, p := range params[1:] {
+			if kv := strings.SplitN(strings.TrimSpace(p), "=", 2); len(kv) == 2 && kv[0] == "q" {
+				c.q, _ = strconv.ParseFloat(kv[1], 64)
+			}
+		}
+
+		specificity := 0
+		switch c.mediaType {
+		case MIMEJSON:
+			specificity = 3
+		case "application/*":
+			specificity = 2
+		case "*/*":
+			specificity = 1
+		}
+		if specificity > jsonSpecificity {
+			jsonQ, jsonSpecificity = c.q, specificity
+		}
+
+		if c.q > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+	// 按 q 排序，q 相同时具体类型优先于通配符，JSON 优先于其他具体类型
+	rank := func(c candidate) int {
+		switch {
+		case c.mediaType == MIMEJSON:
+			return 0
+		case strings.HasSuffix(c.mediaType, "/*"):
+			return 2
+		default:
+			return 1
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].q != candidates[j].q {
+			return candidates[i].q > candidates[j].q
+		}
+		return rank(candidates[i]) < rank(candidates[j])
+	})
+
+	// wildcard 记录不接受 JSON 时的首个通配符，没有更具体的匹配时使用
+	wildcard := ""
+	for _, c := range candidates {
+		switch {
+		case strings.HasSuffix(c.mediaType, "/*"):
+			if jsonQ > 0 && (c.mediaType == "*/*" || c.mediaType == "application/*") {
+				return codecs[MIMEJSON]
+			}
+			if wildcard == "" {
+				wildcard = c.mediaType
+			}
+		default:
+			if found, ok := codecs[c.mediaType]; ok {
+				return found
+			}
+			// 最佳匹配没有对应的 Codec(如浏览器的 text/html)时，可以接受 JSON 则返回 JSON
+			if jsonQ > 0 {
+				return codecs[MIMEJSON]
+			}
+		}
+	}
+
+	if wildcard != "" {
+		prefix := strings.TrimSuffix(wildcard, "*")
+		if wildcard == "*/*" {
+			prefix = ""
+		}
+		for _, mt := range registeredMediaTypes {
+			if mt != MIMEJSON && strings.HasPrefix(mt, prefix) {
+				return codecs[mt]
+			}
+		}
+	}
+	return codecs[MIMEJSON]
+}
+
+// encodeResponse 以 Accept 协商的 Codec 编码，编码失败(如 protobuf 编码非 proto.Message)时退回 JSON
+func encodeResponse(accept string, v interface{}) (string, []byte, error) {
+	c := negotiateCodec(accept)
+	if raw, err := c.Marshal(v); err == nil {
+		return c.ContentType(), raw, nil
+	}
+
+	c = CodecFor(MIMEJSON)
+	raw, err := c.Marshal(v)
+	return c.ContentType(), raw, err
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string {
+	return MIMEJSON + "; charset=utf-8"
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) ContentType() string {
+	return MIMEProtobuf
+}
+
+func (protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, ErrNotProtoMessage
+	}
+	return proto.Marshal(m)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return ErrNotProtoMessage
+	}
+	return proto.Unmarshal(data, m)
+}
+
+type xmlCodec struct{}
+
+func (xmlCodec) ContentType() string {
+	return MIMEXML + "; charset=utf-8"
+}
+
+func (xmlCodec) Marshal(v interface{}) ([]byte, error) {
+	return xml.Marshal(v)
+}
+
+func (xmlCodec) Unmarshal(data []byte, v interface{}) error {
+	return xml.Unmarshal(data, v)
+}
+
+// msgpackHandle 与 gin 的 MsgPack 绑定一致，字段名取自 codec、json tag
+var msgpackHandle = &codec.MsgpackHandle{}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) ContentType() string {
+	return MIMEMsgPack
+}
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var raw []byte
+	err := codec.NewEncoderBytes(&raw, msgpackHandle).Encode(v)
+	return raw, err
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return codec.NewDecoderBytes(data, msgpackHandle).Decode(v)
+}
+
+type yamlCodec struct{}
+
+func (yamlCodec) ContentType() string {
+	return MIMEYAML + "; charset=utf-8"
+}
+
+func (yamlCodec) Marshal(v interface{}) ([]byte, error) {
+	return yaml.Marshal(v)
+}
+
+func (yamlCodec) Unmarshal(data []byte, v interface{}) error {
+	return yaml.Unmarshal(data, v)
+}
+
+// render 以 Accept 协商的格式输出 v
+func render(ctx *gin.Context, code int, v interface{}) error {
+	contentType, raw, err := encodeResponse(ctx.GetHeader("Accept"), v)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return err
+	}
+	ctx.Data(code, contentType, raw)
+	return nil
+}
+
+// bindBody 表单类型交给 gin 的表单绑定(同时包含 querystring)，其余按 Content-Type 选择 Codec 解码
+func bindBody(ctx *gin.Context, obj interface{}) error {
+	switch ctx.ContentType() {
+	case binding.MIMEPOSTForm:
+		return ctx.ShouldBindWith(obj, binding.Form)
+	case binding.MIMEMultipartPOSTForm:
+		return ctx.ShouldBindWith(obj, binding.FormMultipart)
+	}
+
+	// 优先使用 context.init 缓存的请求体，可重复绑定
+	if body, ok := ctx.Get(_BodyName); ok {
+		return decodeBody(ctx.ContentType(), body.([]byte), obj)
+	}
+	body, err := ctx.GetRawData()
+	if err != nil {
+		return err
+	}
+	return decodeBody(ctx.ContentType(), body, obj)
+}
+
+// decodeBody 按 Content-Type 选择 Codec 解码请求体，为空时按 JSON 解码
+func decodeBody(contentType string, body []byte, obj interface{}) error {
+	if contentType == "" {
+		contentType = MIMEJSON
+	}
+	c := CodecFor(contentType)
+	if c == nil {
+		return fmt.Errorf("http: unsupported content type %q", contentType)
+	}
+	return c.Unmarshal(body, obj)
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	stdctx "context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mel2oo/juice/pkg/limiter"
+	"github.com/mel2oo/juice/pkg/limiter/metrics"
+	"github.com/mel2oo/juice/pkg/logger"
+	"github.com/mel2oo/juice/pkg/logger/zap"
+)
+
+// ConcurrencyConfig ConcurrencyLimiter 的配置
+type ConcurrencyConfig struct {
+	// Limiter 为 nil 时使用默认配置的 Gradient 算法，以 http(多个时为 http-2、http-3...)命名并注册到 metrics.Register；
+	// 自行创建的 Limiter 需要调用 metrics.Register 暴露指标
+	Limiter *limiter.Limiter
+	// Priority 请求的优先级，默认为 DefaultPriority
+	Priority func(ctx Context) limiter.Priority
+}
+
+// DefaultPriority /system/ 下的路由(如健康检查)为 PriorityCritical，其他为 PriorityNormal
+func DefaultPriority(ctx Context) limiter.Priority {
+	if strings.HasPrefix(ctx.Path(), "/system/") {
+		return limiter.PriorityCritical
+	}
+	return limiter.PriorityNormal
+}
+
+// ConcurrencyLimiter 返回并发限制中间件，并发数超出请求优先级可用的上限时返回 503(ServiceBusy)及 Retry-After；
+// 响应状态码为 504 或请求超时时视为过载，自适应算法会降低并发上限；
+// 503 及 429 不计入，避免因自身(含内层 ConcurrencyLimiter)拒绝的请求继续降低上限
+func ConcurrencyLimiter(cfg ConcurrencyConfig) HandlerFunc {
+	if cfg.Limiter == nil {
+		cfg.Limiter = newDefaultLimiter()
+	}
+	if cfg.Priority == nil {
+		cfg.Priority = DefaultPriority
+	}
+
+	return func(ctx Context) {
+		token, ok := cfg.Limiter.Acquire(cfg.Priority(ctx))
+		if !ok {
+			ctx.SetHeader("Retry-After", "1")
+			ctx.AbortWithError(NewErrorFromCode(ServiceBusy))
+			return
+		}
+
+		c := ctx.(*context).ctx
+		defer func() {
+			if err := ctx.(*context).abortError(); err != nil && err.GetBusinessCode() == ServiceBusy {
+				token.Done()
+				return
+			}
+			if c.Writer.Status() == http.StatusGatewayTimeout || c.Request.Context().Err() == stdctx.DeadlineExceeded {
+				token.Drop()
+				return
+			}
+			token.Done()
+		}()
+
+		c.Next()
+	}
+}
+
+// defaultLimiters 已创建的默认 Limiter 个数，用于生成不重复的名称
+var defaultLimiters uint32
+
+func newDefaultLimiter() *limiter.Limiter {
+	name := "http"
+	if n := atomic.AddUint32(&defaultLimiters, 1); n > 1 {
+		name += "-" + strconv.Itoa(int(n))
+	}
+
+	l := limiter.New(nil, limiter.WithName(name))
+	if err := metrics.Register(l); err != nil {
+		zap.DefaultLogger.Errorw("register limiter metrics error", "limiter", name, logger.Error(err))
+	}
+	return l
+}
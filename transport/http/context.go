@@ -13,7 +13,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/mel2oo/juice/pkg/logger"
-	"github.com/mel2oo/juice/pkg/logger/zap"
 	"github.com/mel2oo/juice/transport/http/middleware/trace"
 )
 
@@ -36,6 +35,9 @@ const (
 type Context interface {
 	init()
 
+	// ShouldBind* 反序列化后按 binding tag(如 `binding:"required,max=32"`)与 Validator 校验，
+	// 校验失败时返回 *ValidationError，通过 AbortWithError(...WithErr(err)) 返回时 mux 会输出字段明细
+
 	// ShouldBindQuery 反序列化 querystring
 	// tag: `form:"xxx"` (注：不要写成 query)
 	ShouldBindQuery(obj interface{}) error
@@ -57,6 +59,10 @@ type Context interface {
 	// tag: `uri:"xxx"`
 	ShouldBindURI(obj interface{}) error
 
+	// ShouldBind 按 Content-Type 选择 Codec 反序列化请求体(JSON、protobuf、XML、MessagePack、YAML，为空时按 JSON)，
+	// 表单类型同 ShouldBindForm/ShouldBindFormMultipart
+	ShouldBind(obj interface{}) error
+
 	ShouldBindFormMultipart(obj interface{}) error
 	SaveUploadedFile(file *multipart.FileHeader, dst string) error
 
@@ -68,11 +74,11 @@ type Context interface {
 	setTrace(trace Trace)
 	disableTrace()
 
-	// Logger 获取 Logger 对象
+	// Logger 获取请求级 Logger 对象(附带 trace_id、span_id、route、method、user_id)
 	Logger() logger.Logger
 	setLogger(logger logger.Logger)
 
-	// Payload 正确返回
+	// Payload 正确返回，按 Accept 协商响应格式(见 RegisterCodec)，默认 JSON
 	Payload(payload interface{})
 	getPayload() interface{}
 
@@ -85,7 +91,7 @@ type Context interface {
 	FileData(filepath string)
 	FileFromMultipart(filename string, data []byte)
 
-	// AbortWithError 错误返回
+	// AbortWithError 错误返回，Failure 的响应格式与 Payload 相同
 	AbortWithError(err Error)
 	abortError() Error
 
@@ -118,6 +124,8 @@ type Context interface {
 	Host() string
 	// Path 获取 请求的路径 Request.URL.Path (不附带 querystring)
 	Path() string
+	// Route 获取匹配到的路由模板(如 /user/:name)，未匹配时为空
+	Route() string
 	// URI 获取 unescape 后的 Request.URL.RequestURI()
 	URI() string
 	// RequestContext 获取请求的 context (当 client 关闭后，会自动 canceled)
@@ -170,36 +178,55 @@ func (c *context) init() {
 // ShouldBindQuery 反序列化querystring
 // tag: `form:"xxx"` (注：不要写成query)
 func (c *context) ShouldBindQuery(obj interface{}) error {
-	return c.ctx.ShouldBindWith(obj, binding.Query)
+	return c.shouldBindWith(obj, binding.Query)
 }
 
 // ShouldBindPostForm 反序列化 postform (querystring 会被忽略)
 // tag: `form:"xxx"`
 func (c *context) ShouldBindPostForm(obj interface{}) error {
-	return c.ctx.ShouldBindWith(obj, binding.FormPost)
+	return c.shouldBindWith(obj, binding.FormPost)
 }
 
 // ShouldBindForm 同时反序列化querystring和postform;
 // 当querystring和postform存在相同字段时，postform优先使用。
 // tag: `form:"xxx"`
 func (c *context) ShouldBindForm(obj interface{}) error {
-	return c.ctx.ShouldBindWith(obj, binding.Form)
+	return c.shouldBindWith(obj, binding.Form)
 }
 
 // ShouldBindJSON 反序列化postjson
 // tag: `json:"xxx"`
 func (c *context) ShouldBindJSON(obj interface{}) error {
-	return c.ctx.ShouldBindWith(obj, binding.JSON)
+	return c.shouldBindWith(obj, binding.JSON)
 }
 
 // ShouldBindURI 反序列化path参数(如路由路径为 /user/:name)
 // tag: `uri:"xxx"`
 func (c *context) ShouldBindURI(obj interface{}) error {
-	return c.ctx.ShouldBindUri(obj)
+	if err := c.ctx.ShouldBindUri(obj); err != nil {
+		return err
+	}
+	return validateStruct(obj)
+}
+
+// ShouldBind 按 Content-Type 反序列化请求体
+func (c *context) ShouldBind(obj interface{}) error {
+	if err := bindBody(c.ctx, obj); err != nil {
+		return err
+	}
+	return validateStruct(obj)
 }
 
 func (c *context) ShouldBindFormMultipart(obj interface{}) error {
-	return c.ctx.ShouldBindWith(obj, binding.FormMultipart)
+	return c.shouldBindWith(obj, binding.FormMultipart)
+}
+
+// shouldBindWith 反序列化后执行 binding tag 与 Validator 校验，校验失败时返回 *ValidationError
+func (c *context) shouldBindWith(obj interface{}, b binding.Binding) error {
+	if err := c.ctx.ShouldBindWith(obj, b); err != nil {
+		return err
+	}
+	return validateStruct(obj)
 }
 
 func (c *context) SaveUploadedFile(file *multipart.FileHeader, dst string) error {
@@ -229,12 +256,12 @@ func (c *context) disableTrace() {
 }
 
 func (c *context) Logger() logger.Logger {
-	logger, ok := c.ctx.Get(_LoggerName)
+	log, ok := c.ctx.Get(_LoggerName)
 	if !ok {
 		return nil
 	}
 
-	return logger.(*zap.ZapLogger)
+	return log.(logger.Logger)
 }
 
 func (c *context) setLogger(logger logger.Logger) {
@@ -313,6 +340,9 @@ func (c *context) UserID() int64 {
 
 func (c *context) setUserID(userID int64) {
 	c.ctx.Set(_UserID, userID)
+	if log := c.Logger(); log != nil {
+		c.setLogger(log.With(logger.Int64("user_id", userID)))
+	}
 }
 
 func (c *context) UserName() string {
@@ -335,14 +365,17 @@ func (c *context) AbortWithError(err Error) {
 			httpCode = http.StatusInternalServerError
 		}
 
-		c.ctx.AbortWithStatus(httpCode)
+		// 不立即写出响应头，由 mux 按 Accept 输出 Failure 时一并写出 Content-Type
+		c.ctx.Abort()
+		c.ctx.Status(httpCode)
 		c.ctx.Set(_AbortErrorName, err)
 	}
 }
 
 func (c *context) abortError() Error {
 	err, _ := c.ctx.Get(_AbortErrorName)
-	return err.(Error)
+	e, _ := err.(Error)
+	return e
 }
 
 func (c *context) Alias() string {
@@ -389,18 +422,30 @@ func (c *context) Path() string {
 	return c.ctx.Request.URL.Path
 }
 
+// Route 匹配到的路由模板
+func (c *context) Route() string {
+	return c.ctx.FullPath()
+}
+
 // URI unescape后的uri
 func (c *context) URI() string {
 	uri, _ := url.QueryUnescape(c.ctx.Request.URL.RequestURI())
 	return uri
 }
 
-// RequestContext 获取请求的 context (当client关闭后，会自动canceled)
+// RequestContext 获取请求的 context (当client关闭后，会自动canceled)，
+// 其中携带请求级 logger，可通过 logger.FromContext 取出
 func (c *context) RequestContext() StdContext {
+	ctx := c.ctx.Request.Context()
+	log := c.Logger()
+	if log != nil {
+		ctx = logger.ToContext(ctx, log)
+	}
+
 	return StdContext{
-		c.ctx.Request.Context(),
+		ctx,
 		c.Trace(),
-		c.Logger(),
+		log,
 	}
 }
 
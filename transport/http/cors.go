@@ -0,0 +1,137 @@
+package http
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/rs/cors"
+)
+
+// CorsConfig 跨域策略，通过 WithCors 全局启用或通过 RouterGroup.Cors 对路由组启用
+type CorsConfig struct {
+	// AllowedOrigins 允许的来源，支持精确匹配及含一个 * 的通配(如 https://*.example.com)，不区分大小写；
+	// "*" 允许所有来源，不能与 AllowCredentials 同时使用
+	AllowedOrigins []string
+	// AllowedOriginPatterns 以正则表达式匹配来源，需自行添加 ^、$ 锚定完整来源
+	AllowedOriginPatterns []string
+	// AllowedMethods 默认为 GET、HEAD、POST、PUT、PATCH、DELETE
+	AllowedMethods []string
+	// AllowedHeaders 允许的请求 Header，"*" 允许所有，默认为 Origin、Accept、Content-Type、X-Requested-With
+	AllowedHeaders []string
+	// ExposedHeaders 允许浏览器读取的响应 Header，如 RateLimit-Remaining
+	ExposedHeaders []string
+	// MaxAge 预检结果的缓存时间，0 时不返回 Access-Control-Max-Age
+	MaxAge           time.Duration
+	AllowCredentials bool
+}
+
+var defaultCorsMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// originMatcher 按 CorsConfig 的 AllowedOrigins、AllowedOriginPatterns 匹配来源
+type originMatcher struct {
+	all       bool
+	exact     map[string]bool
+	wildcards [][2]string
+	patterns  []*regexp.Regexp
+}
+
+func newOriginMatcher(cfg CorsConfig) (*originMatcher, error) {
+	m := &originMatcher{exact: make(map[string]bool)}
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(origin)
+		switch i := strings.IndexByte(origin, '*'); {
+		case origin == "*":
+			m.all = true
+		case i < 0:
+			m.exact[origin] = true
+		case strings.IndexByte(origin[i+1:], '*') >= 0:
+			return nil, errors.Errorf("cors: origin %q contains more than one wildcard", origin)
+		default:
+			m.wildcards = append(m.wildcards, [2]string{origin[:i], origin[i+1:]})
+		}
+	}
+	for _, pattern := range cfg.AllowedOriginPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cors: invalid origin pattern %q", pattern)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+
+	if m.all && cfg.AllowCredentials {
+		return nil, errors.New("cors: AllowedOrigins \"*\" can not be used with AllowCredentials")
+	}
+	return m, nil
+}
+
+func (m *originMatcher) match(origin string) bool {
+	if m.all {
+		return true
+	}
+
+	lower := strings.ToLower(origin)
+	if m.exact[lower] {
+		return true
+	}
+	for _, w := range m.wildcards {
+		if len(lower) > len(w[0])+len(w[1]) && strings.HasPrefix(lower, w[0]) && strings.HasSuffix(lower, w[1]) {
+			return true
+		}
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// newCors 返回处理跨域请求的 gin 中间件，预检请求以 204 结束，不再执行后续 handler；
+// 允许的来源总是原样回写到 Access-Control-Allow-Origin，并附带 Vary: Origin
+func newCors(cfg CorsConfig) (gin.HandlerFunc, error) {
+	matcher, err := newOriginMatcher(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCorsMethods
+	}
+
+	c := cors.New(cors.Options{
+		AllowOriginFunc:  matcher.match,
+		AllowedMethods:   methods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   cfg.ExposedHeaders,
+		MaxAge:           int(cfg.MaxAge / time.Second),
+		AllowCredentials: cfg.AllowCredentials,
+	})
+
+	return func(ctx *gin.Context) {
+		c.HandlerFunc(ctx.Writer, ctx.Request)
+		if isPreflight(ctx.Request) {
+			ctx.AbortWithStatus(http.StatusNoContent)
+		}
+	}, nil
+}
+
+func isPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// preflight 为启用 RouterGroup.Cors 的路由注册的 OPTIONS 路由，预检请求由之前的跨域中间件处理
+func preflight(ctx *gin.Context) {
+	ctx.Status(http.StatusNoContent)
+}
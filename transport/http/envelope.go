@@ -0,0 +1,107 @@
+package http
+
+// Renderer 将 Payload 或 AbortWithError 的结果转换为响应体：成功时 failure 为 nil，失败(含 panic)时 data 为 nil
+type Renderer func(ctx Context, data interface{}, failure *Failure) interface{}
+
+// Envelope DefaultEnvelope 输出的统一响应结构
+type Envelope struct {
+	Code       int           `json:"code"`                 // 业务码，成功时为 0
+	Message    string        `json:"message"`              // 描述信息
+	Data       interface{}   `json:"data,omitempty"`       // Payload 的数据
+	Details    []FieldError  `json:"details,omitempty"`    // 参数校验失败的字段明细
+	Extra      []interface{} `json:"extra,omitempty"`      // Error.WithDetails 附加的明细
+	Pagination *Pagination   `json:"pagination,omitempty"` // Payload 为 *Page 时的分页信息
+	TraceID    string        `json:"trace_id,omitempty"`
+}
+
+// DefaultEnvelope 输出 {code, message, data, trace_id}，Payload 为 *Page 时 data 为 Items，分页信息输出到 pagination
+func DefaultEnvelope(ctx Context, data interface{}, failure *Failure) interface{} {
+	env := &Envelope{Message: "ok"}
+	if x := ctx.Trace(); x != nil {
+		env.TraceID = x.ID()
+	}
+
+	if failure != nil {
+		env.Code = failure.Code
+		env.Message = failure.Message
+		env.Details = failure.Details
+		env.Extra = failure.Extra
+		return env
+	}
+
+	if page, ok := data.(*Page); ok {
+		env.Data = page.Items
+		env.Pagination = &page.Pagination
+		return env
+	}
+	env.Data = data
+	return env
+}
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 1000
+)
+
+// PageRequest 分页查询参数，可嵌入请求结构体，通过 ShouldBindQuery 或 Handle 绑定
+type PageRequest struct {
+	Page     int `form:"page" json:"page" binding:"omitempty,gte=1"`
+	PageSize int `form:"page_size" json:"page_size" binding:"omitempty,gte=1,lte=1000"`
+}
+
+// Normalize 未设置的 Page 取 1、PageSize 取 DefaultPageSize，PageSize 不超过 MaxPageSize
+func (r *PageRequest) Normalize() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+	if r.PageSize < 1 {
+		r.PageSize = DefaultPageSize
+	}
+	if r.PageSize > MaxPageSize {
+		r.PageSize = MaxPageSize
+	}
+}
+
+// Offset 用于 SQL 的 OFFSET
+func (r PageRequest) Offset() int {
+	r.Normalize()
+	return (r.Page - 1) * r.PageSize
+}
+
+// Limit 用于 SQL 的 LIMIT
+func (r PageRequest) Limit() int {
+	r.Normalize()
+	return r.PageSize
+}
+
+// Pagination 分页信息
+type Pagination struct {
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	HasNext    bool  `json:"has_next"`
+}
+
+// Page 分页结果，未使用 Envelope 时输出 {items, pagination}
+type Page struct {
+	Items      interface{} `json:"items"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// NewPage items 为当前页的数据，total 为总条数
+func NewPage(items interface{}, req PageRequest, total int64) *Page {
+	req.Normalize()
+
+	pages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+	return &Page{
+		Items: items,
+		Pagination: Pagination{
+			Page:       req.Page,
+			PageSize:   req.PageSize,
+			Total:      total,
+			TotalPages: pages,
+			HasNext:    req.Page < pages,
+		},
+	}
+}
@@ -2,21 +2,38 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 
+	jerrors "github.com/mel2oo/juice/pkg/errors"
 	"github.com/pkg/errors"
 )
 
 var _ Error = (*err)(nil)
 
+// Error 业务错误，With* 方法均返回副本，预定义的 Error 可在多个请求间复用；
+// errors.Is 按业务码匹配(包括 pkg/errors 的 *Error)，errors.As 可取出 Error
 type Error interface {
+	error
 	i()
 
+	// WithErr 返回以 err 为原因的副本，err 只用于日志，不会返回给调用方
 	WithErr(err error) Error
+	// WithMsg 返回替换描述信息的副本
+	WithMsg(msg string) Error
+	// WithArgs 返回以 args 格式化描述模板(fmt 格式，如 "用户 %s 不存在")的副本
+	WithArgs(args ...interface{}) Error
+	// WithDetails 返回附加 details 的副本，details 输出到 Failure.Extra
+	WithDetails(details ...interface{}) Error
 
 	GetBusinessCode() int
 	GetHttpCode() int
 	GetMsg() string
 	GetErr() error
+	// GetDetails 返回 details 的副本
+	GetDetails() []interface{}
+
+	Unwrap() error
+	Is(target error) bool
 
 	ToString() string
 }
@@ -26,6 +43,9 @@ type err struct {
 	BusinessCode int
 	Message      string
 	Err          error
+	Details      []interface{}
+	template     string         // WithArgs 使用的描述模板，为创建时的 msg
+	coded        *jerrors.Error // 由注册的业务码创建时用于本地化描述信息
 }
 
 func NewError(httpCode, businessCode int, msg string) Error {
@@ -33,14 +53,113 @@ func NewError(httpCode, businessCode int, msg string) Error {
 		HttpCode:     httpCode,
 		BusinessCode: businessCode,
 		Message:      msg,
+		template:     msg,
+	}
+}
+
+// NewErrorFromCode 使用 errors 包中注册的业务码创建 Error，HTTP 状态码与描述信息取自注册信息，
+// args 为描述模板的参数；返回时描述信息按请求的 Accept-Language 本地化
+func NewErrorFromCode(code int, args ...interface{}) Error {
+	return fromCoded(jerrors.New(code, args...))
+}
+
+// FromError 将 err 转换为 Error：err 链中有 Error 时返回该 Error，
+// 有 *errors.Error(包括 gRPC 客户端还原的)时使用其业务码，否则为 ServerError；err 为 nil 时返回 nil
+func FromError(e error) Error {
+	if e == nil {
+		return nil
+	}
+
+	var he Error
+	if errors.As(e, &he) {
+		return he
+	}
+
+	coded, ok := jerrors.FromError(e)
+	if !ok {
+		return NewErrorFromCode(ServerError).WithErr(e)
 	}
+	return fromCoded(coded).WithErr(e)
+}
+
+func fromCoded(coded *jerrors.Error) *err {
+	return &err{
+		HttpCode:     coded.HTTPStatus(),
+		BusinessCode: coded.Code,
+		Message:      coded.Message,
+		template:     jerrors.Text(coded.Code),
+		coded:        coded,
+	}
+}
+
+// localizedMsg 返回 locale 下的描述信息，不是由注册的业务码创建或经 WithMsg 替换时为 Message
+func (e *err) localizedMsg(locale string) string {
+	if e.coded == nil {
+		return e.Message
+	}
+	return e.coded.Localize(locale)
 }
 
 func (e *err) i() {}
 
+func (e *err) clone() *err {
+	c := *e
+	return &c
+}
+
 func (e *err) WithErr(err error) Error {
-	e.Err = errors.WithStack(err)
-	return e
+	c := e.clone()
+	c.Err = errors.WithStack(err)
+	return c
+}
+
+func (e *err) WithMsg(msg string) Error {
+	c := e.clone()
+	c.Message = msg
+	c.template = msg
+	c.coded = nil
+	return c
+}
+
+func (e *err) WithArgs(args ...interface{}) Error {
+	c := e.clone()
+	if e.coded != nil {
+		c.coded = jerrors.New(e.coded.Code, args...)
+		c.Message = c.coded.Message
+		return c
+	}
+	c.Message = fmt.Sprintf(e.template, args...)
+	return c
+}
+
+func (e *err) WithDetails(details ...interface{}) Error {
+	c := e.clone()
+	c.Details = append(append([]interface{}(nil), e.Details...), details...)
+	return c
+}
+
+func (e *err) Error() string {
+	msg := fmt.Sprintf("http_code=%d business_code=%d message=%s", e.HttpCode, e.BusinessCode, e.Message)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *err) Unwrap() error {
+	return e.Err
+}
+
+// Is 业务码相同即视为同一错误，target 可以是 Error 或 pkg/errors 的 *Error
+func (e *err) Is(target error) bool {
+	switch t := target.(type) {
+	case Error:
+		return t.GetBusinessCode() == e.BusinessCode
+	case *jerrors.Error:
+		return t.Code == e.BusinessCode
+	default:
+		return false
+	}
 }
 
 func (e *err) GetHttpCode() int {
@@ -59,6 +178,14 @@ func (e *err) GetErr() error {
 	return e.Err
 }
 
+// GetDetails 返回 details 的副本，修改返回值不会影响 e
+func (e *err) GetDetails() []interface{} {
+	if len(e.Details) == 0 {
+		return nil
+	}
+	return append([]interface{}(nil), e.Details...)
+}
+
 func (e *err) ToString() string {
 	err := &struct {
 		HttpCode     int    `json:"http_code"`
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -9,12 +10,13 @@ import (
 
 	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
+	jerrors "github.com/mel2oo/juice/pkg/errors"
+	"github.com/mel2oo/juice/pkg/logger"
+	_ "github.com/mel2oo/juice/pkg/logger/metrics" // 注册 logger_dropped_total 指标
 	dlog "github.com/mel2oo/juice/pkg/logger/zap"
 	"github.com/mel2oo/juice/transport/http/middleware/trace"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	cors "github.com/rs/cors/wrapper/gin"
 	"go.uber.org/multierr"
-	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 )
 
@@ -46,49 +48,42 @@ func NewMux(options ...Option) (*Mux, error) {
 	gin.DisableBindValidation()
 	mux := &Mux{
 		engine: gin.New(),
+		routes: new(routes),
 	}
 
 	opt := newOptions()
 	for _, f := range options {
 		f(opt)
 	}
+	mux.apiInfo = opt.openAPIInfo
 
 	if !opt.disablePProf {
 		pprof.Register(mux.engine)
 		dlog.DefaultLogger.Info("register pprof")
 	}
 
-	// if !opt.disableSwagger {
-	// 	mux.engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	// 	dlog.DefaultLogger.Info("[register swagger]")
-	// }
-
 	if !opt.disablePrometheus {
 		mux.engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
 		dlog.DefaultLogger.Info("register prometheus")
 	}
 
-	if opt.enableCors {
-		mux.engine.Use(cors.New(cors.Options{
-			AllowedOrigins: []string{"*"},
-			AllowedMethods: []string{
-				http.MethodHead,
-				http.MethodGet,
-				http.MethodPost,
-				http.MethodPut,
-				http.MethodPatch,
-				http.MethodDelete,
-			},
-			AllowedHeaders:     []string{"*"},
-			AllowCredentials:   true,
-			OptionsPassthrough: true,
-		}))
+	if opt.cors != nil {
+		handler, err := newCors(*opt.cors)
+		if err != nil {
+			return nil, err
+		}
+		mux.engine.Use(func(ctx *gin.Context) {
+			if path := ctx.FullPath(); path != "" && mux.routes.hasCors(path) {
+				return
+			}
+			handler(ctx)
+		})
 	}
 
 	mux.engine.Use(func(ctx *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				opt.log.Error("got panic", zap.String("panic", fmt.Sprintf("%+v", err)), zap.String("stack", string(debug.Stack())))
+				opt.log.Errorw("got panic", "panic", fmt.Sprintf("%+v", err), "stack", string(debug.Stack()))
 			}
 		}()
 
@@ -102,7 +97,6 @@ func NewMux(options ...Option) (*Mux, error) {
 		defer releaseContext(context)
 
 		context.init()
-		context.setLogger(opt.log)
 
 		if !withoutTracePaths[ctx.Request.URL.Path] {
 			if traceId := context.GetHeader(trace.Header); traceId != "" {
@@ -112,22 +106,34 @@ func NewMux(options ...Option) (*Mux, error) {
 			}
 		}
 
+		// 请求级 logger，handler 中通过 ctx.Logger() 或 logger.FromContext(ctx.RequestContext()) 获取
+		fields := []logger.Field{
+			logger.String("span_id", trace.NewSpanID()),
+			logger.String("route", ctx.FullPath()),
+			logger.String("method", ctx.Request.Method),
+		}
+		if x := context.Trace(); x != nil {
+			fields = append(fields, logger.String("trace_id", x.ID()))
+		}
+		context.setLogger(opt.log.With(fields...))
+
 		defer func() {
 			if err := recover(); err != nil {
 				stackInfo := string(debug.Stack())
-				opt.log.Error("got panic", zap.String("panic", fmt.Sprintf("%+v", err)), zap.String("stack", stackInfo))
+				context.Logger().Errorw("got panic", "panic", fmt.Sprintf("%+v", err), "stack", stackInfo)
 				context.AbortWithError(NewError(
 					http.StatusInternalServerError,
 					ServerError,
 					Text(ServerError)),
 				)
 
-				if notify := opt.panicNotify; notify != nil && opt.mailOptions != nil {
+				if notify := opt.panicNotify; notify != nil {
 					notify(context, opt.mailOptions, err, stackInfo)
 				}
 			}
 
-			if ctx.Writer.Status() == http.StatusNotFound {
+			// 未匹配路由的 404 不输出响应体，AbortWithError 返回的 404 照常输出 Failure
+			if ctx.Writer.Status() == http.StatusNotFound && context.abortError() == nil {
 				return
 			}
 
@@ -150,16 +156,29 @@ func NewMux(options ...Option) (*Mux, error) {
 					response = err
 					businessCode = err.GetBusinessCode()
 					businessCodeMsg = err.GetMsg()
+					if l, ok := err.(interface{ localizedMsg(string) string }); ok {
+						businessCodeMsg = l.localizedMsg(jerrors.NegotiateLocale(context.GetHeader("Accept-Language")))
+					}
 
 					if x := context.Trace(); x != nil {
 						context.SetHeader(trace.Header, x.ID())
 						traceId = x.ID()
 					}
 
-					ctx.JSON(err.GetHttpCode(), &Failure{
+					failure := &Failure{
 						Code:    businessCode,
 						Message: businessCodeMsg,
-					})
+						Extra:   err.GetDetails(),
+					}
+					var verr *ValidationError
+					if errors.As(err.GetErr(), &verr) {
+						failure.Details = verr.Localize(negotiateLocale(context.GetHeader("Accept-Language"), opt.validationLocale)).Fields
+					}
+					var body interface{} = failure
+					if opt.renderer != nil {
+						body = opt.renderer(context, nil, failure)
+					}
+					multierr.AppendInto(&abortErr, render(ctx, err.GetHttpCode(), body))
 				}
 			} else {
 				response = context.getPayload()
@@ -168,7 +187,11 @@ func NewMux(options ...Option) (*Mux, error) {
 						context.SetHeader(trace.Header, x.ID())
 						traceId = x.ID()
 					}
-					ctx.JSON(http.StatusOK, response)
+					body := response
+					if opt.renderer != nil {
+						body = opt.renderer(context, response, nil)
+					}
+					multierr.AppendInto(&abortErr, render(ctx, http.StatusOK, body))
 				}
 			}
 
@@ -232,7 +255,7 @@ func NewMux(options ...Option) (*Mux, error) {
 
 			if !opt.disableLogger {
 				if opt.simpleLogger {
-					opt.log.Debug(
+					context.Logger().Debug(
 						fmt.Sprintf("interceptor | method: %s | path: %s | http_code: %d",
 							ctx.Request.Method,
 							decodedURL,
@@ -240,16 +263,19 @@ func NewMux(options ...Option) (*Mux, error) {
 						),
 					)
 				} else {
-					opt.log.Debug("interceptor",
-						zap.Any("method", ctx.Request.Method),
-						zap.Any("path", decodedURL),
-						zap.Any("http_code", ctx.Writer.Status()),
-						zap.Any("business_code", businessCode),
-						zap.Any("success", t.Success),
-						zap.Any("cost_seconds", t.CostSeconds),
-						zap.Any("trace_id", t.Identifier),
-						zap.Any("trace_info", t),
-						zap.Error(abortErr),
+					// 服务端错误以 Error 级别输出，不受采样与限速影响(见 logger.WithAlwaysLogLevel)
+					logw := context.Logger().Debugw
+					if ctx.Writer.Status() >= http.StatusInternalServerError {
+						logw = context.Logger().Errorw
+					}
+					logw("interceptor",
+						"path", decodedURL,
+						"http_code", ctx.Writer.Status(),
+						"business_code", businessCode,
+						"success", t.Success,
+						"cost_seconds", t.CostSeconds,
+						"trace_info", t,
+						logger.Error(abortErr),
 					)
 				}
 			}
@@ -277,6 +303,19 @@ func NewMux(options ...Option) (*Mux, error) {
 		})
 	}
 
+	for _, cfg := range opt.rateLimits {
+		limit := RateLimiter(cfg)
+		mux.engine.Use(wrapHandlers(func(ctx Context) {
+			// 健康检查不受全局限流影响，/system 下的管理与文档接口仍然限流
+			if ctx.Request().URL.Path != "/system/health" {
+				limit(ctx)
+			}
+		})...)
+	}
+	if opt.concurrency != nil {
+		mux.engine.Use(wrapHandlers(ConcurrencyLimiter(*opt.concurrency))...)
+	}
+
 	mux.engine.NoMethod(wrapHandlers(DisableTrace)...)
 	mux.engine.NoRoute(wrapHandlers(DisableTrace)...)
 	system := mux.Group("/system")
@@ -293,6 +332,17 @@ func NewMux(options ...Option) (*Mux, error) {
 			}
 			ctx.Payload(resp)
 		})
+
+		if registry := opt.loggerRegistry; registry != nil {
+			if _, ok := registry.Get("http"); !ok {
+				registry.Register("http", opt.log)
+			}
+			registerLoggerAdmin(system, registry, opt.loggerHandlers...)
+		}
+
+		if opt.enableOpenAPI {
+			registerOpenAPI(mux, system, opt.openAPIViewer, opt.openAPIHandlers...)
+		}
 	}
 
 	return mux, nil
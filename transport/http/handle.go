@@ -0,0 +1,139 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+var (
+	contextType = reflect.TypeOf((*Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*Error)(nil)).Elem()
+)
+
+// Handle 将形如以下签名的函数适配为 HandlerFunc：
+//
+//	func(ctx Context, req *Req) (*Resp, Error)
+//	func(ctx Context, req *Req) Error
+//
+// 每次请求新建 Req，依次按 uri、header、form(querystring)tag 及 Content-Type 对应的 Codec 绑定请求体，
+// 再执行 binding tag 与 Validator 校验，失败时以 ParamBindError 返回；
+// 函数返回的 Error 通过 AbortWithError 返回，否则非 nil 的 Resp 通过 Payload 返回。
+// 签名不符合时 panic
+func Handle(fn interface{}) HandlerFunc {
+	h := newTypedHandler(fn)
+	return h.serve
+}
+
+type typedHandler struct {
+	fn        reflect.Value
+	req       reflect.Type // Req，不含指针
+	hasResp   bool
+	hasURI    bool
+	hasHeader bool
+	hasForm   bool
+}
+
+func newTypedHandler(fn interface{}) *typedHandler {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 2 || t.In(0) != contextType ||
+		t.In(1).Kind() != reflect.Ptr || t.In(1).Elem().Kind() != reflect.Struct ||
+		t.NumOut() < 1 || t.NumOut() > 2 || t.Out(t.NumOut()-1) != errorType {
+		panic(fmt.Sprintf("http: Handle expects func(Context, *Req) (*Resp, Error) or func(Context, *Req) Error, got %s", t))
+	}
+
+	h := &typedHandler{
+		fn:      v,
+		req:     t.In(1).Elem(),
+		hasResp: t.NumOut() == 2,
+	}
+	h.hasURI = hasTag(h.req, "uri")
+	h.hasHeader = hasTag(h.req, "header")
+	h.hasForm = hasTag(h.req, "form")
+	return h
+}
+
+// hasTag 判断结构体(含嵌入结构体)是否有字段带有 tag
+func hasTag(t reflect.Type, tag string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if _, ok := f.Tag.Lookup(tag); ok {
+			return true
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && ft.Kind() == reflect.Struct && hasTag(ft, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *typedHandler) serve(ctx Context) {
+	req := reflect.New(h.req)
+	if err := h.bind(ctx, req.Interface()); err != nil {
+		ctx.AbortWithError(NewError(
+			http.StatusBadRequest,
+			ParamBindError,
+			Text(ParamBindError)).WithErr(err),
+		)
+		return
+	}
+
+	out := h.fn.Call([]reflect.Value{reflect.ValueOf(ctx), req})
+	if err := out[len(out)-1]; !err.IsNil() {
+		ctx.AbortWithError(err.Interface().(Error))
+		return
+	}
+
+	if h.hasResp {
+		if resp := out[0]; !isNil(resp) {
+			ctx.Payload(resp.Interface())
+		}
+	}
+}
+
+// bind 各来源绑定完成后统一校验，避免只绑定了部分来源时 required 等规则误报
+func (h *typedHandler) bind(ctx Context, obj interface{}) error {
+	c := ctx.(*context).ctx
+
+	if h.hasURI {
+		if err := c.ShouldBindUri(obj); err != nil {
+			return err
+		}
+	}
+	if h.hasHeader {
+		if err := c.ShouldBindWith(obj, binding.Header); err != nil {
+			return err
+		}
+	}
+	if h.hasForm {
+		if err := c.ShouldBindWith(obj, binding.Query); err != nil {
+			return err
+		}
+	}
+
+	method := c.Request.Method
+	if method != http.MethodGet && method != http.MethodHead && len(ctx.RawData()) > 0 {
+		if err := bindBody(c, obj); err != nil {
+			return err
+		}
+	}
+
+	return validateStruct(obj)
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
@@ -1,33 +1,150 @@
 package notify
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/logger"
 	"github.com/mel2oo/juice/pkg/mail"
+	"github.com/mel2oo/juice/pkg/notifier"
+	"github.com/mel2oo/juice/transport"
 	"github.com/mel2oo/juice/transport/http"
-	"go.uber.org/zap"
 )
 
-func OnPanicNotify(ctx http.Context, options *mail.Options, err interface{}, stackInfo string) {
+type Option func(*option)
+
+type option struct {
+	registry      *notifier.Registry
+	locale        string
+	redactHeaders []string
+}
+
+// WithRegistry 使用自定义的模板注册表，默认 notifier.DefaultRegistry
+func WithRegistry(registry *notifier.Registry) Option {
+	return func(o *option) {
+		o.registry = registry
+	}
+}
+
+// WithLocale 指定模板语言，默认使用注册表的默认语言
+func WithLocale(locale string) Option {
+	return func(o *option) {
+		o.locale = locale
+	}
+}
 
-	subject, body, htmlErr := NewPanicHTMLEmail(
-		ctx.Method(),
-		ctx.Host(),
-		ctx.URI(),
-		ctx.Trace().ID(),
-		err,
-		stackInfo,
-	)
-	if htmlErr != nil {
-		ctx.Logger().Error("NewPanicHTMLEmail error", zap.Error(htmlErr))
+// WithRedactHeaders 额外需要脱敏的 header
+func WithRedactHeaders(keys ...string) Option {
+	return func(o *option) {
+		o.redactHeaders = append(o.redactHeaders, keys...)
+	}
+}
+
+// OnPanicNotify 通过 http.WithMailOptions 设置的邮件配置发送 panic 告警，邮件由每个 mail.Options 共享的
+// mail.Sender 异步发送，不阻塞请求；将 Senders() 交给 juice.Server 管理，退出时会发送完队列中的邮件
+func OnPanicNotify(ctx http.Context, options *mail.Options, err interface{}, stackInfo string) {
+	if options == nil {
 		return
 	}
 
-	options.Subject = subject
-	options.Body = body
+	NewPanicNotify(senderFor(options))(ctx, options, err, stackInfo)
+}
+
+var (
+	sendersMux sync.Mutex
+	senders    = make(map[*mail.Options]*mail.Sender)
+	group      = &senderGroup{done: make(chan struct{})}
+)
+
+func senderFor(options *mail.Options) *mail.Sender {
+	sendersMux.Lock()
+	defer sendersMux.Unlock()
+
+	s, ok := senders[options]
+	if !ok {
+		s = mail.NewSender(options)
+		senders[options] = s
+	}
+	return s
+}
+
+// Senders 返回 OnPanicNotify 创建的所有 mail.Sender 组成的 transport.Server，
+// Stop 时等待各 Sender 发送完队列中的邮件
+func Senders() transport.Server {
+	return group
+}
+
+type senderGroup struct {
+	once sync.Once
+	done chan struct{}
+}
+
+func (g *senderGroup) Start() error {
+	<-g.done
+	return nil
+}
+
+func (g *senderGroup) Stop() error {
+	sendersMux.Lock()
+	stopping := make([]*mail.Sender, 0, len(senders))
+	for options, s := range senders {
+		stopping = append(stopping, s)
+		delete(senders, options)
+	}
+	sendersMux.Unlock()
 
-	sendErr := mail.Send(options)
-	if sendErr != nil {
-		ctx.Logger().Error("Mail Send error", zap.Error(sendErr))
+	for _, s := range stopping {
+		s.Stop()
 	}
 
-	return
+	g.once.Do(func() { close(g.done) })
+	return nil
+}
+
+// NewPanicNotify 通过任意 notifier.Notifier 发送 panic 告警，配合 http.WithPanicNotify 使用；
+// 正文格式(HTML/Markdown)由 notifier.FormatOf 决定。
+// 发送在请求 goroutine 中执行，n 应当是异步的(如 mail.Sender)或经 notifier.Dispatcher 限流。
+func NewPanicNotify(n notifier.Notifier, opts ...Option) http.OnPanicNotify {
+	o := &option{
+		registry: notifier.DefaultRegistry,
+	}
+	for _, f := range opts {
+		f(o)
+	}
+
+	return func(ctx http.Context, _ *mail.Options, err interface{}, stackInfo string) {
+		alert := &notifier.Alert{
+			UserID:   ctx.UserID(),
+			UserName: ctx.UserName(),
+			Method:   ctx.Method(),
+			Host:     ctx.Host(),
+			Route:    ctx.Route(),
+			URL:      ctx.URI(),
+			Header:   notifier.RedactHeader(ctx.Request().Header, o.redactHeaders...),
+			Error:    fmt.Sprintf("%+v", err),
+			Stack:    stackInfo,
+			Time:     time.Now(),
+		}
+		if t := ctx.Trace(); t != nil {
+			alert.TraceID = t.ID()
+		}
+
+		subject, body, renderErr := o.registry.Render(notifier.EventPanic, o.locale, notifier.FormatOf(n), alert)
+		if renderErr != nil {
+			ctx.Logger().Errorw("Render panic notify error", logger.Error(renderErr))
+			return
+		}
+
+		var sendErr error
+		if fn, ok := n.(notifier.FingerprintNotifier); ok {
+			sendErr = fn.SendWithFingerprint(fmt.Sprintf("%s %s %v", ctx.Method(), ctx.Route(), err), subject, body)
+		} else {
+			sendErr = n.Send(subject, body)
+		}
+
+		if sendErr != nil {
+			ctx.Logger().Errorw("Notify Send error", logger.Error(sendErr))
+		}
+	}
 }
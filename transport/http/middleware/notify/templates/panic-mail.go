@@ -1,5 +1,7 @@
 package templates
 
+// PanicMail 旧版 panic 告警邮件模板，渲染数据需包含 ID、URL、Msg 等字段
+// Deprecated: 使用 notifier.Registry 中的 notifier.EventPanic 模板(见 pkg/notifier/templates)，其渲染数据为 notifier.Alert
 const PanicMail = `
 <!DOCTYPE html>
 <html>
@@ -4,7 +4,10 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"io"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const Header = "TRACE-ID"
@@ -69,6 +72,18 @@ func New(id string) *Trace {
 	}
 }
 
+var spanSeq uint64
+
+// NewSpanID 生成当前服务处理本次请求的 span 标识，读取随机数失败时使用纳秒时间戳与进程内序号，
+// 避免生成全零的标识
+func NewSpanID() string {
+	buf := make([]byte, 8)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16) + strconv.FormatUint(atomic.AddUint64(&spanSeq, 1), 16)
+	}
+	return hex.EncodeToString(buf)
+}
+
 func (t *Trace) i() {}
 
 // ID 唯一标识符
@@ -0,0 +1,469 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// OpenAPIInfo OpenAPI 文档的 info 部分
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas,omitempty"`
+}
+
+type openAPIOperation struct {
+	Tags        []string                    `json:"tags,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIBody                `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	MinLength            *float64                  `json:"minLength,omitempty"`
+	MaxLength            *float64                  `json:"maxLength,omitempty"`
+	MinItems             *float64                  `json:"minItems,omitempty"`
+	MaxItems             *float64                  `json:"maxItems,omitempty"`
+}
+
+// OpenAPIJSON 根据已注册的路由生成 OpenAPI 3 文档(JSON)，/system 下的内置接口不输出
+func (m *Mux) OpenAPIJSON() ([]byte, error) {
+	return json.MarshalIndent(m.openAPI(), "", "  ")
+}
+
+// OpenAPIYAML 与 OpenAPIJSON 相同，以 YAML 输出
+func (m *Mux) OpenAPIYAML() ([]byte, error) {
+	raw, err := json.Marshal(m.openAPI())
+	if err != nil {
+		return nil, err
+	}
+
+	// 借助 MapSlice 保持字段顺序
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// WriteOpenAPI 将 OpenAPI 文档写入 filename，扩展名为 .yaml、.yml 时以 YAML 输出，否则为 JSON
+func (m *Mux) WriteOpenAPI(filename string) error {
+	var (
+		raw []byte
+		err error
+	)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		raw, err = m.OpenAPIYAML()
+	default:
+		raw, err = m.OpenAPIJSON()
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, raw, 0644)
+}
+
+var pathParamRegexp = regexp.MustCompile(`[:*]([^/]+)`)
+
+func (m *Mux) openAPI() *openAPIDocument {
+	info := m.apiInfo
+	if info.Title == "" {
+		info.Title = "API"
+	}
+	if info.Version == "" {
+		info.Version = "1.0.0"
+	}
+
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]map[string]*openAPIOperation),
+	}
+	b := &schemaBuilder{
+		schemas: make(map[string]*openAPISchema),
+		names:   make(map[reflect.Type]string),
+	}
+
+	for _, route := range m.Routes() {
+		if route.Path == "/system" || strings.HasPrefix(route.Path, "/system/") {
+			continue
+		}
+
+		p := pathParamRegexp.ReplaceAllString(route.Path, "{$1}")
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = make(map[string]*openAPIOperation)
+		}
+		doc.Paths[p][strings.ToLower(route.Method)] = b.operation(route)
+	}
+
+	doc.Components.Schemas = b.schemas
+	return doc
+}
+
+// schemaBuilder 命名的结构体类型输出到 components/schemas 并以 $ref 引用
+type schemaBuilder struct {
+	schemas map[string]*openAPISchema
+	names   map[reflect.Type]string
+}
+
+func (b *schemaBuilder) operation(route Route) *openAPIOperation {
+	op := &openAPIOperation{
+		Tags:        route.Tags,
+		Summary:     route.Summary,
+		Description: route.Description,
+		Deprecated:  route.Deprecated,
+		Responses:   make(map[string]*openAPIResponse),
+	}
+
+	inPath := make(map[string]bool)
+	if route.Request != nil {
+		op.Parameters = b.parameters(route.Request)
+		for _, p := range op.Parameters {
+			if p.In == "path" {
+				inPath[p.Name] = true
+			}
+		}
+
+		if body := b.body(route.Request); body != nil && route.Method != http.MethodGet && route.Method != http.MethodHead {
+			op.RequestBody = &openAPIBody{
+				Required: len(body.Required) > 0,
+				Content:  map[string]*openAPIMediaType{"application/json": {Schema: body}},
+			}
+		}
+	}
+	// 未通过 Handle 声明的路径参数按字符串输出
+	for _, m := range pathParamRegexp.FindAllStringSubmatch(route.Path, -1) {
+		if !inPath[m[1]] {
+			op.Parameters = append(op.Parameters, &openAPIParameter{
+				Name: m[1], In: "path", Required: true, Schema: &openAPISchema{Type: "string"},
+			})
+		}
+	}
+
+	ok := &openAPIResponse{Description: http.StatusText(http.StatusOK)}
+	if route.Response != nil {
+		ok.Content = map[string]*openAPIMediaType{"application/json": {Schema: b.schema(route.Response, "")}}
+	}
+	op.Responses[strconv.Itoa(http.StatusOK)] = ok
+
+	errs := route.Errors
+	if route.Request != nil {
+		errs = append([]Error{NewError(http.StatusBadRequest, ParamBindError, Text(ParamBindError))}, errs...)
+	}
+	for _, err := range errs {
+		code := strconv.Itoa(err.GetHttpCode())
+		desc := fmt.Sprintf("%d: %s", err.GetBusinessCode(), err.GetMsg())
+		if resp, ok := op.Responses[code]; ok {
+			resp.Description += "\n" + desc
+			continue
+		}
+		op.Responses[code] = &openAPIResponse{
+			Description: desc,
+			Content:     map[string]*openAPIMediaType{"application/json": {Schema: b.schema(failureType, "")}},
+		}
+	}
+	return op
+}
+
+var parameterTags = []struct{ tag, in string }{
+	{"uri", "path"},
+	{"form", "query"},
+	{"header", "header"},
+}
+
+// parameters 带 uri、form、header tag 的字段输出为 path、query、header 参数
+func (b *schemaBuilder) parameters(t reflect.Type) []*openAPIParameter {
+	var params []*openAPIParameter
+	eachField(t, func(f reflect.StructField) {
+		for _, pt := range parameterTags {
+			name := tagName(f, pt.tag)
+			if name == "" {
+				continue
+			}
+			params = append(params, &openAPIParameter{
+				Name:     name,
+				In:       pt.in,
+				Required: pt.in == "path" || hasRule(f, "required"),
+				Schema:   b.schema(f.Type, f.Tag.Get("binding")),
+			})
+		}
+	})
+	return params
+}
+
+// body 请求体为不带 uri、form、header tag 的字段，没有这样的字段时返回 nil
+func (b *schemaBuilder) body(t reflect.Type) *openAPISchema {
+	s := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	eachField(t, func(f reflect.StructField) {
+		for _, pt := range parameterTags {
+			if _, ok := f.Tag.Lookup(pt.tag); ok {
+				return
+			}
+		}
+		b.property(s, f)
+	})
+
+	if len(s.Properties) == 0 {
+		return nil
+	}
+	return s
+}
+
+// eachField 遍历导出字段，未命名的嵌入结构体展开
+func eachField(t reflect.Type, fn func(f reflect.StructField)) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && ft.Kind() == reflect.Struct && tagName(f, "json") == "" {
+			eachField(ft, fn)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		fn(f)
+	}
+}
+
+func tagName(f reflect.StructField, tag string) string {
+	name := strings.SplitN(f.Tag.Get(tag), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func (b *schemaBuilder) property(s *openAPISchema, f reflect.StructField) {
+	if f.Tag.Get("json") == "-" {
+		return
+	}
+	name := tagName(f, "json")
+	if name == "" {
+		name = f.Name
+	}
+	s.Properties[name] = b.schema(f.Type, f.Tag.Get("binding"))
+	if hasRule(f, "required") {
+		s.Required = append(s.Required, name)
+	}
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	failureType = reflect.TypeOf(Failure{})
+)
+
+// schema rules 为字段的 binding tag，映射为 minimum、maxLength、enum 等约束
+func (b *schemaBuilder) schema(t reflect.Type, rules string) *openAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var s *openAPISchema
+	switch t.Kind() {
+	case reflect.Bool:
+		s = &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		s = &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		s = &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		s = &openAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		s = &openAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		s = &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			s = &openAPISchema{Type: "string", Format: "byte"}
+			break
+		}
+		s = &openAPISchema{Type: "array", Items: b.schema(t.Elem(), diveRules(rules))}
+	case reflect.Map:
+		s = &openAPISchema{Type: "object", AdditionalProperties: b.schema(t.Elem(), "")}
+	case reflect.Struct:
+		if t == timeType {
+			s = &openAPISchema{Type: "string", Format: "date-time"}
+			break
+		}
+		if t.Name() == "" {
+			s = b.object(t)
+			break
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + b.component(t)}
+	default:
+		s = &openAPISchema{}
+	}
+
+	applyRules(s, rules)
+	return s
+}
+
+func (b *schemaBuilder) object(t reflect.Type) *openAPISchema {
+	s := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	eachField(t, func(f reflect.StructField) {
+		b.property(s, f)
+	})
+	return s
+}
+
+// component 返回命名结构体在 components/schemas 中的名称，不同包的同名类型加包名区分
+func (b *schemaBuilder) component(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, ok := b.schemas[name]; ok {
+		name = filepath.Base(t.PkgPath()) + "." + name
+	}
+	b.names[t] = name
+	// 先占位，避免递归类型无限展开
+	b.schemas[name] = &openAPISchema{}
+	*b.schemas[name] = *b.object(t)
+	return name
+}
+
+func hasRule(f reflect.StructField, rule string) bool {
+	for _, r := range strings.Split(f.Tag.Get("binding"), ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// diveRules 返回 dive 之后作用于元素的规则
+func diveRules(rules string) string {
+	if i := strings.Index(rules, "dive"); i >= 0 {
+		return strings.TrimPrefix(rules[i+len("dive"):], ",")
+	}
+	return ""
+}
+
+func applyRules(s *openAPISchema, rules string) {
+	if i := strings.Index(rules, "dive"); i >= 0 {
+		rules = rules[:i]
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		kv := strings.SplitN(rule, "=", 2)
+		if len(kv) != 2 {
+			switch kv[0] {
+			case "email":
+				s.Format = "email"
+			case "url", "uri":
+				s.Format = "uri"
+			case "uuid":
+				s.Format = "uuid"
+			case "ipv4":
+				s.Format = "ipv4"
+			case "ipv6":
+				s.Format = "ipv6"
+			}
+			continue
+		}
+
+		name, param := kv[0], kv[1]
+		if name == "oneof" {
+			if s.Type == "string" {
+				s.Enum = strings.Fields(param)
+			}
+			continue
+		}
+		v, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			continue
+		}
+
+		switch s.Type {
+		case "string":
+			switch name {
+			case "min":
+				s.MinLength = &v
+			case "max":
+				s.MaxLength = &v
+			case "len":
+				s.MinLength, s.MaxLength = &v, &v
+			}
+		case "array":
+			switch name {
+			case "min":
+				s.MinItems = &v
+			case "max":
+				s.MaxItems = &v
+			case "len":
+				s.MinItems, s.MaxItems = &v, &v
+			}
+		case "integer", "number":
+			switch name {
+			case "min", "gte":
+				s.Minimum = &v
+			case "max", "lte":
+				s.Maximum = &v
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package http
+
+// openAPIEmbeddedPage 不依赖外部资源的文档浏览页面，读取同目录下的 openapi.json，
+// 按 tag 列出接口及其参数、请求体与响应结构，离线环境下可用
+const openAPIEmbeddedPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>API Reference</title>
+  <style>
+    body { margin: 0; padding: 24px 40px; font: 14px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #222; }
+    h1 { margin: 0 0 4px; font-size: 26px; }
+    h2 { margin: 28px 0 8px; font-size: 18px; border-bottom: 1px solid #ddd; padding-bottom: 4px; }
+    .desc { color: #555; white-space: pre-wrap; }
+    details { margin: 6px 0; border: 1px solid #ddd; border-radius: 4px; }
+    summary { padding: 8px 12px; cursor: pointer; }
+    .op { padding: 4px 16px 12px; }
+    .method { display: inline-block; min-width: 64px; margin-right: 8px; padding: 2px 6px; border-radius: 3px;
+      color: #fff; font-weight: bold; text-align: center; text-transform: uppercase; }
+    .get { background: #61affe; } .post { background: #49cc90; } .put { background: #fca130; }
+    .patch { background: #50e3c2; } .delete { background: #f93e3e; } .head, .options { background: #9012fe; }
+    .path { font-family: monospace; font-size: 15px; }
+    .deprecated .path { text-decoration: line-through; }
+    table { border-collapse: collapse; margin: 4px 0 8px; }
+    th, td { padding: 4px 10px; border: 1px solid #e5e5e5; text-align: left; vertical-align: top; }
+    pre { margin: 4px 0 8px; padding: 8px; background: #f6f8fa; border-radius: 3px; overflow: auto; }
+    h4 { margin: 10px 0 2px; }
+  </style>
+</head>
+<body>
+  <div id="doc">Loading openapi.json ...</div>
+  <script>
+    (function () {
+      var root = document.getElementById("doc");
+
+      function el(tag, attrs, children) {
+        var e = document.createElement(tag);
+        for (var k in attrs || {}) {
+          if (k === "text") e.textContent = attrs[k]; else e.setAttribute(k, attrs[k]);
+        }
+        (children || []).forEach(function (c) { if (c) e.appendChild(c); });
+        return e;
+      }
+
+      // resolve 展开 #/components/... 引用，seen 防止循环引用
+      function resolve(spec, schema, seen) {
+        if (!schema || typeof schema !== "object") return schema;
+        seen = seen || [];
+        if (schema.$ref) {
+          if (seen.indexOf(schema.$ref) >= 0) return { $ref: schema.$ref };
+          var target = schema.$ref.replace(/^#\//, "").split("/").reduce(function (o, k) { return o && o[k]; }, spec);
+          return resolve(spec, target, seen.concat(schema.$ref));
+        }
+        var out = Array.isArray(schema) ? [] : {};
+        for (var k in schema) out[k] = resolve(spec, schema[k], seen);
+        return out;
+      }
+
+      function schemaBlock(spec, content) {
+        var nodes = [];
+        for (var type in content || {}) {
+          nodes.push(el("div", { text: type }));
+          nodes.push(el("pre", { text: JSON.stringify(resolve(spec, content[type].schema), null, 2) }));
+        }
+        return nodes;
+      }
+
+      function operation(spec, path, method, op) {
+        var body = [];
+        if (op.description) body.push(el("p", { "class": "desc", text: op.description }));
+
+        var params = (op.parameters || []).map(function (p) { return resolve(spec, p); });
+        if (params.length) {
+          body.push(el("h4", { text: "Parameters" }));
+          body.push(el("table", {}, [el("tr", {}, ["Name", "In", "Type", "Required", "Description"].map(function (h) {
+            return el("th", { text: h });
+          }))].concat(params.map(function (p) {
+            var s = p.schema || {};
+            return el("tr", {}, [p.name, p.in, s.type || "", p.required ? "yes" : "", p.description || ""].map(function (v) {
+              return el("td", { text: v });
+            }));
+          }))));
+        }
+
+        if (op.requestBody) {
+          body.push(el("h4", { text: "Request body" }));
+          body = body.concat(schemaBlock(spec, resolve(spec, op.requestBody).content));
+        }
+
+        for (var code in op.responses || {}) {
+          var resp = resolve(spec, op.responses[code]);
+          body.push(el("h4", { text: "Response " + code + (resp.description ? " - " + resp.description : "") }));
+          body = body.concat(schemaBlock(spec, resp.content));
+        }
+
+        return el("details", { "class": op.deprecated ? "deprecated" : "" }, [
+          el("summary", {}, [
+            el("span", { "class": "method " + method, text: method }),
+            el("span", { "class": "path", text: path }),
+            op.summary ? el("span", { "class": "desc", text: "  " + op.summary }) : null
+          ]),
+          el("div", { "class": "op" }, body)
+        ]);
+      }
+
+      function render(spec) {
+        var info = spec.info || {};
+        var groups = {}, order = [];
+        for (var path in spec.paths || {}) {
+          for (var method in spec.paths[path]) {
+            var op = spec.paths[path][method];
+            if (typeof op !== "object" || method === "parameters") continue;
+            var tag = (op.tags && op.tags[0]) || "default";
+            if (!groups[tag]) { groups[tag] = []; order.push(tag); }
+            groups[tag].push(operation(spec, path, method, op));
+          }
+        }
+
+        root.textContent = "";
+        root.appendChild(el("h1", { text: (info.title || "API") + (info.version ? " " + info.version : "") }));
+        if (info.description) root.appendChild(el("p", { "class": "desc", text: info.description }));
+        order.forEach(function (tag) {
+          root.appendChild(el("h2", { text: tag }));
+          groups[tag].forEach(function (n) { root.appendChild(n); });
+        });
+      }
+
+      fetch("openapi.json")
+        .then(function (r) { if (!r.ok) throw new Error(r.status + " " + r.statusText); return r.json(); })
+        .then(render)
+        .catch(function (e) { root.textContent = "Failed to load openapi.json: " + e.message; });
+    })();
+  </script>
+</body>
+</html>`
@@ -1,34 +1,48 @@
 package http
 
 import (
+	"net/http"
+
 	"github.com/mel2oo/juice/pkg/logger"
 	"github.com/mel2oo/juice/pkg/logger/zap"
 	"github.com/mel2oo/juice/pkg/mail"
+	"github.com/mel2oo/juice/pkg/signature"
 )
 
 type Option func(*option)
 
 type option struct {
-	log          logger.Logger
-	disablePProf bool
-	// disableSwagger    bool
+	log               logger.Logger
+	disablePProf      bool
 	disablePrometheus bool
 	disableLogger     bool
 	simpleLogger      bool
 	panicNotify       OnPanicNotify
 	mailOptions       *mail.Options
 	recordMetrics     RecordMetrics
-	enableCors        bool
+	cors              *CorsConfig
 	enableRate        bool
+	rateLimits        []RateLimitConfig
+	concurrency       *ConcurrencyConfig
+	loggerRegistry    *logger.Registry
+	loggerHandlers    []HandlerFunc
+	validationLocale  string
+	enableOpenAPI     bool
+	openAPIInfo       OpenAPIInfo
+	openAPIViewer     *SwaggerUIAssets
+	openAPIHandlers   []HandlerFunc
+	renderer          Renderer
 }
 
+// OnPanicNotify panic 时的通知回调，opts 为 WithMailOptions 设置的邮件配置(未设置时为 nil)
 type OnPanicNotify func(ctx Context, opts *mail.Options, err interface{}, stackInfo string)
 
 type RecordMetrics func(method, uri string, success bool, httpCode, businessCode int, costSeconds float64, traceId string)
 
 func newOptions() *option {
 	return &option{
-		log: zap.DefaultLogger,
+		log:              zap.DefaultLogger,
+		validationLocale: DefaultValidationLocale,
 	}
 }
 
@@ -44,12 +58,6 @@ func WithDisablePProf() Option {
 	}
 }
 
-// func WithDisableSwagger() Option {
-// 	return func(opt *option) {
-// 		opt.disableSwagger = true
-// 	}
-// }
-
 func WithDisableproPrometheus() Option {
 	return func(opt *option) {
 		opt.disablePrometheus = true
@@ -87,13 +95,26 @@ func WithRecordMetrics(record RecordMetrics) Option {
 	}
 }
 
+// Deprecated: 允许所有来源且不允许携带凭证，使用 WithCors 配置来源白名单
 func WithEnableCors() Option {
+	return WithCors(CorsConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedHeaders: []string{"*"},
+	})
+}
+
+// WithCors 对所有请求应用跨域策略 cfg，cfg 无效时 NewMux 返回错误；
+// 通过 RouterGroup.Cors 设置了跨域策略的路由不使用 cfg
+func WithCors(cfg CorsConfig) Option {
 	return func(opt *option) {
-		opt.enableCors = true
+		opt.cors = &cfg
 		zap.DefaultLogger.Info("register cors")
 	}
 }
 
+// WithEnableRate 所有请求共享一个全局令牌桶限流
+//
+// Deprecated: 使用 WithRateLimit，可以按 IP、用户等维度设置限额
 func WithEnableRate() Option {
 	return func(opt *option) {
 		opt.enableRate = true
@@ -101,6 +122,77 @@ func WithEnableRate() Option {
 	}
 }
 
+// WithRateLimit 对所有路由(/system/health 除外)启用 RateLimiter，可多次调用叠加多个维度的限额(如按 IP 与按路由)；
+// 需要对路由组单独设置限额时在 Group 中使用 RateLimiter
+func WithRateLimit(cfg RateLimitConfig) Option {
+	return func(opt *option) {
+		opt.rateLimits = append(opt.rateLimits, cfg)
+		zap.DefaultLogger.Info("register rate limit")
+	}
+}
+
+// WithConcurrencyLimit 对所有路由启用 ConcurrencyLimiter，在 WithRateLimit 之后执行
+func WithConcurrencyLimit(cfg ConcurrencyConfig) Option {
+	return func(opt *option) {
+		opt.concurrency = &cfg
+		zap.DefaultLogger.Info("register concurrency limit")
+	}
+}
+
+// WithLoggerAdmin 注册 /system/loggers 日志级别管理接口，registry 为 nil 时使用 logger.DefaultRegistry，
+// Mux 的 logger 未注册时以 "http" 注册；handlers 在管理接口前执行，用于鉴权，如 WrapSignatureHandler
+func WithLoggerAdmin(registry *logger.Registry, handlers ...HandlerFunc) Option {
+	return func(opt *option) {
+		if registry == nil {
+			registry = logger.DefaultRegistry
+		}
+		opt.loggerRegistry = registry
+		opt.loggerHandlers = handlers
+		zap.DefaultLogger.Info("register logger admin")
+	}
+}
+
+// WithValidationLocale 设置参数校验错误明细的默认语言，请求的 Accept-Language 匹配到已注册的语言时优先使用，
+// 内置 zh、en，其他语言通过 RegisterValidationMessages 注册
+func WithValidationLocale(locale string) Option {
+	return func(opt *option) {
+		opt.validationLocale = locale
+	}
+}
+
+// WithOpenAPI 注册 /system/openapi.json、/system/openapi.yaml，根据注册的路由实时生成 OpenAPI 3 文档；
+// handlers 在文档接口前执行，用于鉴权
+func WithOpenAPI(info OpenAPIInfo, handlers ...HandlerFunc) Option {
+	return func(opt *option) {
+		opt.enableOpenAPI = true
+		opt.openAPIInfo = info
+		opt.openAPIHandlers = handlers
+		zap.DefaultLogger.Info("register openapi")
+	}
+}
+
+// WithOpenAPIViewer 在 WithOpenAPI 的基础上注册 /system/openapi 文档浏览页面，
+// 未指定 assets 时使用内置的离线页面，指定时使用 assets 加载的 Swagger UI
+func WithOpenAPIViewer(assets ...SwaggerUIAssets) Option {
+	return func(opt *option) {
+		var viewer SwaggerUIAssets
+		if len(assets) > 0 {
+			viewer = assets[0]
+		}
+		opt.openAPIViewer = &viewer
+	}
+}
+
+// WithEnvelope 使用 renderer 包装 Payload、AbortWithError 及 panic 的响应体，renderer 为 nil 时使用 DefaultEnvelope
+func WithEnvelope(renderer Renderer) Option {
+	return func(opt *option) {
+		if renderer == nil {
+			renderer = DefaultEnvelope
+		}
+		opt.renderer = renderer
+	}
+}
+
 func DisableTrace(ctx Context) {
 	ctx.disableTrace()
 }
@@ -125,3 +217,16 @@ func WrapAuthHandler(handler func(Context) (userID int64, userName string, err E
 		ctx.setUserName(userName)
 	}
 }
+
+// WrapSignatureHandler 校验 httpclient.WithSigner 生成的 HMAC 签名，校验失败时返回 401。
+func WrapSignatureHandler(verifier *signature.Verifier) HandlerFunc {
+	return func(ctx Context) {
+		if _, err := verifier.VerifyRequest(ctx.Request(), ctx.RawData()); err != nil {
+			ctx.AbortWithError(NewError(
+				http.StatusUnauthorized,
+				SignatureError,
+				Text(SignatureError)).WithErr(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package http
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimit 令牌桶参数：每秒补充 Rate 个令牌，桶容量为 Burst
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerSecond 每秒 n 次，允许 n 次突发
+func PerSecond(n int) RateLimit {
+	return RateLimit{Rate: float64(n), Burst: n}
+}
+
+// PerMinute 每分钟 n 次，允许 n 次突发
+func PerMinute(n int) RateLimit {
+	return RateLimit{Rate: float64(n) / 60, Burst: n}
+}
+
+// RateLimitResult 一次取令牌的结果
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int           // 桶容量
+	Remaining  int           // 剩余令牌数
+	ResetAfter time.Duration // 令牌补满所需时间
+	RetryAfter time.Duration // 被拒绝时，下一个令牌可用所需时间
+}
+
+// RateLimitStore 限流状态的存储，实现共享存储(如 Redis)可在多个实例间共享限额
+type RateLimitStore interface {
+	// Take 从 key 的令牌桶中取一个令牌
+	Take(key string, limit RateLimit) (RateLimitResult, error)
+}
+
+// RateLimitKeyFunc 返回请求所属的限流维度，返回空字符串时不限流
+type RateLimitKeyFunc func(ctx Context) string
+
+// KeyByIP 按连接的对端 IP 限流，不信任 X-Forwarded-For 等 Header；
+// 服务部署在代理之后时使用 KeyByClientIP
+func KeyByIP(ctx Context) string {
+	return "ip:" + remoteIP(ctx.Request())
+}
+
+// KeyByClientIP 按客户端 IP 限流：对端 IP 属于 trustedProxies(IP 或 CIDR)时，
+// 取 X-Forwarded-For 中从右向左第一个不属于 trustedProxies 的地址，没有时使用 X-Real-Ip；
+// 其他情况与 KeyByIP 相同。trustedProxies 格式错误时 panic
+func KeyByClientIP(trustedProxies ...string) RateLimitKeyFunc {
+	nets := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, p := range trustedProxies {
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil && ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			panic(fmt.Sprintf("http: invalid trusted proxy %q: %v", p, err))
+		}
+		nets = append(nets, n)
+	}
+	trusted := func(s string) bool {
+		ip := net.ParseIP(strings.TrimSpace(s))
+		if ip == nil {
+			return false
+		}
+		for _, n := range nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(ctx Context) string {
+		req := ctx.Request()
+		ip := remoteIP(req)
+		if !trusted(ip) {
+			return "ip:" + ip
+		}
+
+		forwarded := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+		for i := len(forwarded) - 1; i >= 0; i-- {
+			addr := strings.TrimSpace(forwarded[i])
+			if net.ParseIP(addr) == nil {
+				break
+			}
+			if !trusted(addr) {
+				return "ip:" + addr
+			}
+		}
+		if addr := strings.TrimSpace(req.Header.Get("X-Real-Ip")); net.ParseIP(addr) != nil {
+			return "ip:" + addr
+		}
+		return "ip:" + ip
+	}
+}
+
+// remoteIP 返回 RemoteAddr 中的 IP
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(req.RemoteAddr))
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// KeyByUserID 按 WrapAuthHandler 设置的 UserID 限流，未登录时按客户端 IP
+func KeyByUserID(ctx Context) string {
+	if id := ctx.UserID(); id != 0 {
+		return "user:" + strconv.FormatInt(id, 10)
+	}
+	return KeyByIP(ctx)
+}
+
+// KeyByHeader 按 Header(如 X-API-Key)的值限流，Header 为空时按客户端 IP
+func KeyByHeader(header string) RateLimitKeyFunc {
+	return func(ctx Context) string {
+		if v := ctx.GetHeader(header); v != "" {
+			return "header:" + v
+		}
+		return KeyByIP(ctx)
+	}
+}
+
+// KeyByRoute 同一路由模板的所有请求共享限额
+func KeyByRoute(ctx Context) string {
+	return "route:" + ctx.Method() + " " + ctx.Route()
+}
+
+// RateLimitConfig RateLimiter 的配置
+type RateLimitConfig struct {
+	Limit RateLimit
+	// Key 默认为 KeyByIP
+	Key RateLimitKeyFunc
+	// Store 默认为每个 RateLimiter 独立的 MemoryRateLimitStore
+	Store RateLimitStore
+	// Name 区分不同 RateLimiter 的计数，为空时自动生成；多个实例共享 Store 时需显式设置
+	Name string
+}
+
+var rateLimiterSeq uint64
+
+// RateLimiter 返回限流中间件，可用于 Group 以对不同路由组设置不同限额：
+// 响应携带 RateLimit-Limit、RateLimit-Remaining、RateLimit-Reset(秒)，超出限额时返回 429 及 Retry-After；
+// Store 出错时放行
+func RateLimiter(cfg RateLimitConfig) HandlerFunc {
+	if cfg.Key == nil {
+		cfg.Key = KeyByIP
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryRateLimitStore(0)
+	}
+	if cfg.Name == "" {
+		cfg.Name = fmt.Sprintf("ratelimit-%d", atomic.AddUint64(&rateLimiterSeq, 1))
+	}
+
+	return func(ctx Context) {
+		key := cfg.Key(ctx)
+		if key == "" {
+			return
+		}
+
+		res, err := cfg.Store.Take(cfg.Name+"|"+key, cfg.Limit)
+		if err != nil {
+			ctx.Logger().Warnw("rate limit store failed, request allowed", "limiter", cfg.Name, "error", err)
+			return
+		}
+
+		ctx.SetHeader("RateLimit-Limit", strconv.Itoa(res.Limit))
+		ctx.SetHeader("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		ctx.SetHeader("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+
+		if !res.Allowed {
+			retry := ceilSeconds(res.RetryAfter)
+			if retry < 1 {
+				retry = 1
+			}
+			ctx.SetHeader("Retry-After", strconv.Itoa(retry))
+			ctx.AbortWithError(NewErrorFromCode(TooManyRequests))
+		}
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// DefaultRateLimitCapacity MemoryRateLimitStore 默认最多保存的 key 数量
+const DefaultRateLimitCapacity = 10000
+
+// MemoryRateLimitStore 进程内的令牌桶存储，超出容量时淘汰最久未访问的 key
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	buckets  map[string]*list.Element
+	now      func() time.Time
+}
+
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// NewMemoryRateLimitStore capacity 小于等于 0 时使用 DefaultRateLimitCapacity
+func NewMemoryRateLimitStore(capacity int) *MemoryRateLimitStore {
+	if capacity <= 0 {
+		capacity = DefaultRateLimitCapacity
+	}
+	return &MemoryRateLimitStore{
+		capacity: capacity,
+		ll:       list.New(),
+		buckets:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(key string, limit RateLimit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	burst := float64(limit.Burst)
+
+	var b *bucket
+	if e, ok := s.buckets[key]; ok {
+		s.ll.MoveToFront(e)
+		b = e.Value.(*bucket)
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+		b.last = now
+	} else {
+		b = &bucket{key: key, tokens: burst, last: now}
+		s.buckets[key] = s.ll.PushFront(b)
+		if s.ll.Len() > s.capacity {
+			oldest := s.ll.Back()
+			s.ll.Remove(oldest)
+			delete(s.buckets, oldest.Value.(*bucket).key)
+		}
+	}
+
+	res := RateLimitResult{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else if limit.Rate > 0 {
+		res.RetryAfter = time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	} else {
+		res.RetryAfter = time.Duration(math.MaxInt64)
+	}
+
+	res.Remaining = int(math.Floor(b.tokens))
+	if limit.Rate > 0 {
+		res.ResetAfter = time.Duration((burst - b.tokens) / limit.Rate * float64(time.Second))
+	}
+	return res, nil
+}
+
+// Len 返回当前保存的 key 数量
+func (s *MemoryRateLimitStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ll.Len()
+}
+
+var _ RateLimitStore = (*MemoryRateLimitStore)(nil)
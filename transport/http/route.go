@@ -0,0 +1,114 @@
+package http
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Route 路由元数据，用于生成 OpenAPI 文档
+type Route struct {
+	Method      string
+	Path        string // 完整路由模板，如 /user/:name
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	Request     reflect.Type // 通过 Handle 注册时为 Req 的类型
+	Response    reflect.Type // 通过 Handle 注册且有返回值时为 Resp 的类型
+	Errors      []Error      // 可能返回的业务错误
+}
+
+// RouteOption 补充 Route 的文档信息
+type RouteOption func(*Route)
+
+// Summary 接口摘要
+func Summary(summary string) RouteOption {
+	return func(r *Route) {
+		r.Summary = summary
+	}
+}
+
+// Description 接口详细说明
+func Description(description string) RouteOption {
+	return func(r *Route) {
+		r.Description = description
+	}
+}
+
+// Tags 接口分组
+func Tags(tags ...string) RouteOption {
+	return func(r *Route) {
+		r.Tags = append(r.Tags, tags...)
+	}
+}
+
+// Deprecated 标记接口已废弃
+func Deprecated() RouteOption {
+	return func(r *Route) {
+		r.Deprecated = true
+	}
+}
+
+// Errors 接口可能返回的业务错误，按 HTTP 状态码输出到文档
+func Errors(errs ...Error) RouteOption {
+	return func(r *Route) {
+		r.Errors = append(r.Errors, errs...)
+	}
+}
+
+type routes struct {
+	mu      sync.RWMutex
+	routes  []*Route
+	options map[string]bool // 已注册 OPTIONS 的路径
+	cors    map[string]bool // 由 RouterGroup.Cors 处理跨域的路径，全局的跨域策略不再处理
+}
+
+func (r *routes) add(route *Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes = append(r.routes, route)
+}
+
+// addOptions 记录 path 已注册 OPTIONS，已记录时返回 false
+func (r *routes) addOptions(path string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.options[path] {
+		return false
+	}
+	if r.options == nil {
+		r.options = make(map[string]bool)
+	}
+	r.options[path] = true
+	return true
+}
+
+func (r *routes) addCors(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cors == nil {
+		r.cors = make(map[string]bool)
+	}
+	r.cors[path] = true
+}
+
+func (r *routes) hasCors(path string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cors[path]
+}
+
+func (r *routes) list() []Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]Route, len(r.routes))
+	for i, route := range r.routes {
+		list[i] = *route
+	}
+	return list
+}
@@ -2,12 +2,17 @@ package http
 
 import (
 	"net/http"
+	"path"
 
 	"github.com/gin-gonic/gin"
 )
 
 type RouterGroup interface {
 	Group(string, ...HandlerFunc) RouterGroup
+	// Cors 返回应用跨域策略 cfg 的子路由组(路径不变)，其中注册的路由自动注册处理预检请求的 OPTIONS 路由，
+	// 因此不能再为这些路径注册 OPTIONS；预检请求不经过父路由组的 handler(如鉴权)，
+	// 实际请求的跨域中间件在父路由组的 handler 之后执行；cfg 无效时 panic
+	Cors(cfg CorsConfig) RouterGroup
 	IRoutes
 }
 
@@ -22,47 +27,126 @@ type IRoutes interface {
 	PUT(string, ...HandlerFunc)
 	OPTIONS(string, ...HandlerFunc)
 	HEAD(string, ...HandlerFunc)
+
+	// Handle 以 Handle(fn) 注册 typed handler，并记录请求、响应类型及 opts 描述的文档信息
+	Handle(method, relativePath string, fn interface{}, opts ...RouteOption)
 }
 
 type router struct {
-	group *gin.RouterGroup
+	engine *gin.Engine
+	group  *gin.RouterGroup
+	routes *routes
+	// cors 路由组的跨域中间件，非 nil 时为路由注册预检请求的 OPTIONS 路由
+	cors gin.HandlerFunc
 }
 
 func (r *router) Group(relativePath string, handlers ...HandlerFunc) RouterGroup {
 	group := r.group.Group(relativePath, wrapHandlers(handlers...)...)
-	return &router{group: group}
+	return &router{engine: r.engine, group: group, routes: r.routes, cors: r.cors}
 }
 
+func (r *router) Cors(cfg CorsConfig) RouterGroup {
+	handler, err := newCors(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return &router{engine: r.engine, group: r.group.Group("", handler), routes: r.routes, cors: handler}
+}
+
+// Any 注册所有方法，不记录到路由元数据；包括 OPTIONS，启用了 Cors 的路由组中预检请求仍由跨域策略处理
 func (r *router) Any(relativePath string, handlers ...HandlerFunc) {
 	r.group.Any(relativePath, wrapHandlers(handlers...)...)
+
+	path := r.fullPath(relativePath)
+	r.routes.addOptions(path)
+	if r.cors != nil {
+		r.routes.addCors(path)
+	}
 }
 
 func (r *router) GET(relativePath string, handlers ...HandlerFunc) {
 	r.group.GET(relativePath, wrapHandlers(handlers...)...)
+	r.add(relativePath, &Route{Method: http.MethodGet, Path: r.fullPath(relativePath)})
 }
 
 func (r *router) POST(relativePath string, handlers ...HandlerFunc) {
 	r.group.POST(relativePath, wrapHandlers(handlers...)...)
+	r.add(relativePath, &Route{Method: http.MethodPost, Path: r.fullPath(relativePath)})
 }
 
 func (r *router) DELETE(relativePath string, handlers ...HandlerFunc) {
 	r.group.DELETE(relativePath, wrapHandlers(handlers...)...)
+	r.add(relativePath, &Route{Method: http.MethodDelete, Path: r.fullPath(relativePath)})
 }
 
 func (r *router) PATCH(relativePath string, handlers ...HandlerFunc) {
 	r.group.PATCH(relativePath, wrapHandlers(handlers...)...)
+	r.add(relativePath, &Route{Method: http.MethodPatch, Path: r.fullPath(relativePath)})
 }
 
 func (r *router) PUT(relativePath string, handlers ...HandlerFunc) {
 	r.group.PUT(relativePath, wrapHandlers(handlers...)...)
+	r.add(relativePath, &Route{Method: http.MethodPut, Path: r.fullPath(relativePath)})
 }
 
 func (r *router) OPTIONS(relativePath string, handlers ...HandlerFunc) {
 	r.group.OPTIONS(relativePath, wrapHandlers(handlers...)...)
+	r.add(relativePath, &Route{Method: http.MethodOptions, Path: r.fullPath(relativePath)})
 }
 
 func (r *router) HEAD(relativePath string, handlers ...HandlerFunc) {
 	r.group.HEAD(relativePath, wrapHandlers(handlers...)...)
+	r.add(relativePath, &Route{Method: http.MethodHead, Path: r.fullPath(relativePath)})
+}
+
+func (r *router) Handle(method, relativePath string, fn interface{}, opts ...RouteOption) {
+	h := newTypedHandler(fn)
+	r.group.Handle(method, relativePath, wrapHandlers(h.serve)...)
+
+	route := &Route{
+		Method:  method,
+		Path:    r.fullPath(relativePath),
+		Request: h.req,
+	}
+	if h.hasResp {
+		route.Response = h.fn.Type().Out(0)
+	}
+	for _, o := range opts {
+		o(route)
+	}
+	r.add(relativePath, route)
+}
+
+// add 记录路由元数据，启用了 Cors 的路由组为路径注册 OPTIONS 路由以处理预检请求；
+// 预检请求不携带凭证，OPTIONS 路由直接注册在 engine 上，不经过父路由组的鉴权等 handler
+func (r *router) add(relativePath string, route *Route) {
+	r.routes.add(route)
+
+	if route.Method == http.MethodOptions {
+		r.routes.addOptions(route.Path)
+		return
+	}
+	if r.cors == nil {
+		return
+	}
+	r.routes.addCors(route.Path)
+	if r.routes.addOptions(route.Path) {
+		r.engine.OPTIONS(route.Path, r.cors, preflight)
+	}
+}
+
+// fullPath 与 gin 拼接路由路径的方式一致，保留结尾的 /
+func (r *router) fullPath(relativePath string) string {
+	base := r.group.BasePath()
+	if relativePath == "" {
+		return base
+	}
+
+	p := path.Join(base, relativePath)
+	if relativePath[len(relativePath)-1] == '/' && p[len(p)-1] != '/' {
+		p += "/"
+	}
+	return p
 }
 
 func wrapHandlers(handlers ...HandlerFunc) []gin.HandlerFunc {
@@ -89,7 +173,9 @@ func wrapHandlers(handlers ...HandlerFunc) []gin.HandlerFunc {
 // }
 
 type Mux struct {
-	engine *gin.Engine
+	engine  *gin.Engine
+	routes  *routes
+	apiInfo OpenAPIInfo
 }
 
 func (m *Mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -98,6 +184,13 @@ func (m *Mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 func (m *Mux) Group(relativePath string, handlers ...HandlerFunc) RouterGroup {
 	return &router{
-		group: m.engine.Group(relativePath, wrapHandlers(handlers...)...),
+		engine: m.engine,
+		group:  m.engine.Group(relativePath, wrapHandlers(handlers...)...),
+		routes: m.routes,
 	}
 }
+
+// Routes 返回通过 Group 注册的路由元数据，按注册顺序排列
+func (m *Mux) Routes() []Route {
+	return m.routes.list()
+}
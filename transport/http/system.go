@@ -0,0 +1,157 @@
+package http
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/mel2oo/juice/pkg/logger"
+)
+
+// loggerName /system/loggers/:name 的路径参数
+type loggerName struct {
+	Name string `uri:"name"`
+}
+
+// setLevelRequest PUT /system/loggers/:name 的请求体，RevertAfter 如 "10m"，为空表示不自动恢复
+type setLevelRequest struct {
+	Level       string `json:"level"`
+	RevertAfter string `json:"revert_after"`
+}
+
+// registerLoggerAdmin 在 /system 下注册日志级别管理接口：
+//
+//	GET /system/loggers          列出已注册 Logger 的级别
+//	GET /system/loggers/:name    查看单个 Logger 的级别
+//	PUT /system/loggers/:name    修改级别，{"level":"debug","revert_after":"10m"}
+func registerLoggerAdmin(system RouterGroup, registry *logger.Registry, handlers ...HandlerFunc) {
+	group := system.Group("/loggers", handlers...)
+
+	group.GET("", func(ctx Context) {
+		ctx.Payload(registry.Levels())
+	})
+
+	group.GET("/:name", func(ctx Context) {
+		req := new(loggerName)
+		if err := ctx.ShouldBindURI(req); err != nil {
+			ctx.AbortWithError(NewError(http.StatusBadRequest, ParamBindError, Text(ParamBindError)).WithErr(err))
+			return
+		}
+
+		info, err := registry.Level(req.Name)
+		if err != nil {
+			ctx.AbortWithError(NewError(http.StatusBadRequest, ParamBindError, Text(ParamBindError)).WithErr(err))
+			return
+		}
+		ctx.Payload(info)
+	})
+
+	group.PUT("/:name", func(ctx Context) {
+		name := new(loggerName)
+		req := new(setLevelRequest)
+		if err := ctx.ShouldBindURI(name); err != nil {
+			ctx.AbortWithError(NewError(http.StatusBadRequest, ParamBindError, Text(ParamBindError)).WithErr(err))
+			return
+		}
+		if err := ctx.ShouldBindJSON(req); err != nil {
+			ctx.AbortWithError(NewError(http.StatusBadRequest, ParamBindError, Text(ParamBindError)).WithErr(err))
+			return
+		}
+
+		level, err := logger.ParseLevel(req.Level)
+		if err != nil {
+			ctx.AbortWithError(NewError(http.StatusBadRequest, ParamBindError, Text(ParamBindError)).WithErr(err))
+			return
+		}
+
+		var revertAfter time.Duration
+		if req.RevertAfter != "" {
+			if revertAfter, err = time.ParseDuration(req.RevertAfter); err != nil {
+				ctx.AbortWithError(NewError(http.StatusBadRequest, ParamBindError, Text(ParamBindError)).WithErr(err))
+				return
+			}
+		}
+
+		info, err := registry.SetLevel(name.Name, level, revertAfter)
+		if err != nil {
+			ctx.AbortWithError(NewError(http.StatusBadRequest, ParamBindError, Text(ParamBindError)).WithErr(err))
+			return
+		}
+
+		ctx.Logger().Warnw("logger level changed",
+			"logger", info.Name,
+			"level", info.Level,
+			"revert_after", revertAfter,
+		)
+		ctx.Payload(info)
+	})
+}
+
+// SwaggerUIAssets 文档浏览页面使用 Swagger UI 时加载的静态资源，可以指向自建的地址或 CDN，
+// Integrity 非空时作为 Subresource Integrity 校验，如 "sha384-..."
+type SwaggerUIAssets struct {
+	CSS          string
+	CSSIntegrity string
+	JS           string
+	JSIntegrity  string
+}
+
+// openAPIViewerPage Swagger UI 页面，加载同目录下的 openapi.json
+var openAPIViewerPage = template.Must(template.New("openapi").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>API Reference</title>
+  <link rel="stylesheet" href="{{.CSS}}"{{with .CSSIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}>
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="{{.JS}}"{{with .JSIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>`))
+
+// registerOpenAPI 在 /system 下注册 OpenAPI 文档接口：
+//
+//	GET /system/openapi.json  JSON 格式文档
+//	GET /system/openapi.yaml  YAML 格式文档
+//	GET /system/openapi       文档浏览页面(viewer 非 nil 时)，viewer 未指定 JS 时使用内置页面
+func registerOpenAPI(mux *Mux, system RouterGroup, viewer *SwaggerUIAssets, handlers ...HandlerFunc) {
+	group := system.Group("", handlers...)
+
+	group.GET("/openapi.json", func(ctx Context) {
+		raw, err := mux.OpenAPIJSON()
+		if err != nil {
+			ctx.AbortWithError(NewError(http.StatusInternalServerError, ServerError, Text(ServerError)).WithErr(err))
+			return
+		}
+		ctx.ResponseWriter().Header().Set("Content-Type", "application/json; charset=utf-8")
+		ctx.ResponseWriter().Write(raw)
+	})
+
+	group.GET("/openapi.yaml", func(ctx Context) {
+		raw, err := mux.OpenAPIYAML()
+		if err != nil {
+			ctx.AbortWithError(NewError(http.StatusInternalServerError, ServerError, Text(ServerError)).WithErr(err))
+			return
+		}
+		ctx.ResponseWriter().Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		ctx.ResponseWriter().Write(raw)
+	})
+
+	if viewer != nil {
+		var page bytes.Buffer
+		if viewer.JS == "" {
+			page.WriteString(openAPIEmbeddedPage)
+		} else if err := openAPIViewerPage.Execute(&page, viewer); err != nil {
+			panic(err)
+		}
+		group.GET("/openapi", func(ctx Context) {
+			ctx.ResponseWriter().Header().Set("Content-Type", "text/html; charset=utf-8")
+			ctx.ResponseWriter().Write(page.Bytes())
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package http
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Validator 绑定的结构体可实现 Validate 做跨字段等自定义校验，在 tag 校验通过后由 ShouldBind* 调用；
+// 返回 *ValidationError 时按字段输出，返回其他错误时作为一条 rule 为 "validate" 的字段错误
+type Validator interface {
+	Validate() error
+}
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`           // 字段路径，如 items[0].name，名称取自 json/form/uri/header tag
+	Rule    string `json:"rule"`            // 未通过的规则，如 required、min
+	Param   string `json:"param,omitempty"` // 规则参数，如 min=3 中的 3
+	Message string `json:"message"`         // 描述信息
+
+	kind string // string、number、slice，用于选择不同的描述模板
+}
+
+// ValidationError ShouldBind* 的校验错误，mux 渲染 Failure 时将 Fields 按请求语言输出到 details
+type ValidationError struct {
+	Fields []FieldError
+	locale string
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Message
+		if msgs[i] == "" {
+			msgs[i] = f.Field + " " + f.Rule
+		}
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Localize 返回以 locale 生成描述信息的副本，Message 已设置且不来自模板的字段(如 Validate 返回的)保持原样
+func (e *ValidationError) Localize(locale string) *ValidationError {
+	c := &ValidationError{Fields: make([]FieldError, len(e.Fields)), locale: locale}
+	for i, f := range e.Fields {
+		if f.Message == "" || f.Message == validationMessage(e.locale, f) {
+			f.Message = validationMessage(locale, f)
+		}
+		c.Fields[i] = f
+	}
+	return c
+}
+
+// DefaultValidationLocale 校验错误描述信息的默认语言，可通过 WithValidationLocale 修改
+const DefaultValidationLocale = "zh"
+
+var (
+	validationMu       sync.RWMutex
+	validationMessages = map[string]map[string]string{
+		"zh": {
+			"required":      "{field}为必填字段",
+			"required_if":   "{field}为必填字段",
+			"required_with": "{field}为必填字段",
+			"len":           "{field}必须为{param}",
+			"len.string":    "{field}长度必须为{param}个字符",
+			"len.slice":     "{field}必须包含{param}项",
+			"min":           "{field}最小只能为{param}",
+			"min.string":    "{field}长度不能少于{param}个字符",
+			"min.slice":     "{field}至少包含{param}项",
+			"max":           "{field}必须小于或等于{param}",
+			"max.string":    "{field}长度不能超过{param}个字符",
+			"max.slice":     "{field}最多只能包含{param}项",
+			"eq":            "{field}不等于{param}",
+			"ne":            "{field}不能等于{param}",
+			"gt":            "{field}必须大于{param}",
+			"gte":           "{field}必须大于或等于{param}",
+			"lt":            "{field}必须小于{param}",
+			"lte":           "{field}必须小于或等于{param}",
+			"oneof":         "{field}必须是[{param}]中的一个",
+			"email":         "{field}必须是一个有效的邮箱",
+			"url":           "{field}必须是一个有效的URL",
+			"uuid":          "{field}必须是一个有效的UUID",
+			"ip":            "{field}必须是一个有效的IP地址",
+			"numeric":       "{field}必须是一个有效的数值",
+			"alpha":         "{field}只能包含字母",
+			"alphanum":      "{field}只能包含字母和数字",
+			"datetime":      "{field}的格式必须是{param}",
+			"validate":      "{field}校验失败",
+			"":              "{field}校验失败({rule})",
+		},
+		"en": {
+			"required":      "{field} is a required field",
+			"required_if":   "{field} is a required field",
+			"required_with": "{field} is a required field",
+			"len":           "{field} must be equal to {param}",
+			"len.string":    "{field} must be {param} characters in length",
+			"len.slice":     "{field} must contain {param} items",
+			"min":           "{field} must be {param} or greater",
+			"min.string":    "{field} must be at least {param} characters in length",
+			"min.slice":     "{field} must contain at least {param} items",
+			"max":           "{field} must be {param} or less",
+			"max.string":    "{field} must be a maximum of {param} characters in length",
+			"max.slice":     "{field} must contain at maximum {param} items",
+			"eq":            "{field} is not equal to {param}",
+			"ne":            "{field} should not be equal to {param}",
+			"gt":            "{field} must be greater than {param}",
+			"gte":           "{field} must be {param} or greater",
+			"lt":            "{field} must be less than {param}",
+			"lte":           "{field} must be {param} or less",
+			"oneof":         "{field} must be one of [{param}]",
+			"email":         "{field} must be a valid email address",
+			"url":           "{field} must be a valid URL",
+			"uuid":          "{field} must be a valid UUID",
+			"ip":            "{field} must be a valid IP address",
+			"numeric":       "{field} must be a valid numeric value",
+			"alpha":         "{field} can only contain alphabetic characters",
+			"alphanum":      "{field} can only contain alphanumeric characters",
+			"datetime":      "{field} does not match the {param} format",
+			"validate":      "{field} is invalid",
+			"":              "{field} failed on the '{rule}' rule",
+		},
+	}
+)
+
+// RegisterValidationMessages 注册或覆盖 locale 下各规则的描述模板，模板中可使用 {field}、{param}、{rule}；
+// 规则名可加 .string、.number、.slice 后缀区分字段类型，"" 为未匹配规则时的兜底模板
+func RegisterValidationMessages(locale string, messages map[string]string) {
+	validationMu.Lock()
+	defer validationMu.Unlock()
+
+	m, ok := validationMessages[locale]
+	if !ok {
+		m = make(map[string]string, len(messages))
+		validationMessages[locale] = m
+	}
+	for rule, tmpl := range messages {
+		m[rule] = tmpl
+	}
+}
+
+// RegisterValidation 注册自定义校验规则，用于 binding tag
+func RegisterValidation(tag string, fn validator.Func) error {
+	return validate.RegisterValidation(tag, fn)
+}
+
+// validationMessage 按 locale 生成 f 的描述信息，未注册的 locale 使用 DefaultValidationLocale
+func validationMessage(locale string, f FieldError) string {
+	validationMu.RLock()
+	defer validationMu.RUnlock()
+
+	m, ok := validationMessages[locale]
+	if !ok {
+		m = validationMessages[DefaultValidationLocale]
+	}
+
+	tmpl, ok := m[f.Rule+"."+f.kind]
+	if !ok {
+		if tmpl, ok = m[f.Rule]; !ok {
+			tmpl = m[""]
+		}
+	}
+
+	field := f.Field
+	if field == "" {
+		field = "request"
+	}
+	return strings.NewReplacer("{field}", field, "{param}", f.Param, "{rule}", f.Rule).Replace(tmpl)
+}
+
+// negotiateLocale 根据 Accept-Language 选择已注册的语言，如 en-US 匹配 en，均未匹配时返回 def
+func negotiateLocale(acceptLanguage, def string) string {
+	validationMu.RLock()
+	defer validationMu.RUnlock()
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		if _, ok := validationMessages[tag]; ok {
+			return tag
+		}
+		if i := strings.IndexByte(tag, '-'); i > 0 {
+			if _, ok := validationMessages[tag[:i]]; ok {
+				return tag[:i]
+			}
+		}
+	}
+	return def
+}
+
+// validate 使用 gin 约定的 binding tag，如 `json:"name" binding:"required,max=32"`
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, key := range []string{"json", "form", "uri", "header"} {
+			name := strings.SplitN(field.Tag.Get(key), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	return v
+}()
+
+// validateStruct 先执行 binding tag 校验，通过后调用 Validator.Validate
+func validateStruct(obj interface{}) error {
+	if kindOfData(obj) == reflect.Struct {
+		if err := validate.Struct(obj); err != nil {
+			var verrs validator.ValidationErrors
+			if !errors.As(err, &verrs) {
+				return err
+			}
+
+			fields := make([]FieldError, len(verrs))
+			for i, fe := range verrs {
+				fields[i] = FieldError{
+					Field: fieldPath(reflect.TypeOf(obj), fe.Namespace(), fe.StructNamespace()),
+					Rule:  fe.Tag(),
+					Param: fe.Param(),
+					kind:  kindClass(fe.Kind()),
+				}
+			}
+			return newValidationError(fields)
+		}
+	}
+
+	v, ok := obj.(Validator)
+	if !ok {
+		return nil
+	}
+	err := v.Validate()
+	if err == nil {
+		return nil
+	}
+
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return newValidationError(verr.Fields)
+	}
+	return &ValidationError{Fields: []FieldError{{Rule: "validate", Message: err.Error()}}}
+}
+
+func newValidationError(fields []FieldError) *ValidationError {
+	e := &ValidationError{Fields: fields}
+	return e.Localize(DefaultValidationLocale)
+}
+
+func kindOfData(obj interface{}) reflect.Kind {
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	return v.Kind()
+}
+
+// fieldPath 去掉顶层结构体名及嵌入结构体名，如 createUser.Page.items[0].name -> items[0].name；
+// structNamespace 为对应的 Go 字段名路径，用于判断哪一级是嵌入字段
+func fieldPath(t reflect.Type, namespace, structNamespace string) string {
+	names := strings.Split(namespace, ".")
+	fields := strings.Split(structNamespace, ".")
+	if len(names) != len(fields) {
+		return strings.Join(names[1:], ".")
+	}
+
+	var path []string
+	for i := 1; i < len(fields); i++ {
+		t = derefType(t)
+		if t.Kind() != reflect.Struct {
+			path = append(path, names[i:]...)
+			break
+		}
+
+		name := fields[i]
+		if j := strings.IndexByte(name, '['); j >= 0 {
+			name = name[:j]
+		}
+		f, ok := t.FieldByName(name)
+		if !ok {
+			path = append(path, names[i:]...)
+			break
+		}
+		if !f.Anonymous {
+			path = append(path, names[i])
+		}
+
+		t = f.Type
+		if strings.IndexByte(fields[i], '[') >= 0 {
+			// 切片、数组、map 的元素
+			for t = derefType(t); t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map; t = derefType(t) {
+				t = t.Elem()
+			}
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func kindClass(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "slice"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return ""
+	}
+}